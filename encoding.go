@@ -0,0 +1,190 @@
+// Copyright © 2022 Mark Summerfield. All rights reserved.
+// License: Apache-2.0
+
+package gset
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// MarshalJSON returns the set as a JSON array with its elements sorted by <,
+// so the output is stable (e.g., for diffs and tests). A nil set is
+// marshalled as an empty array. See also [Set.MarshalJSONUnsorted].
+func (me Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(me.ToSortedSlice())
+}
+
+// MarshalJSONUnsorted returns the set as a JSON array with its elements in
+// an arbitrary order, which may differ from call to call. For large sets
+// this is faster than [Set.MarshalJSON] since it avoids the sort.
+// A nil set is marshalled as an empty array.
+func (me Set[T]) MarshalJSONUnsorted() ([]byte, error) {
+	return json.Marshal(me.ToSlice())
+}
+
+// MarshalJSONIndent is like [Set.MarshalJSON] but, like json.MarshalIndent,
+// puts each element on its own line beginning with prefix and indented by
+// indent. An empty (or nil) set is marshalled as "[]".
+func (me Set[T]) MarshalJSONIndent(prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(me.ToSortedSlice(), prefix, indent)
+}
+
+// UnmarshalJSON adds the elements from the given JSON array to this set,
+// allocating the set if it is nil. (Like json.Unmarshal into a map, any
+// existing elements are kept.) As the encoding/json convention requires,
+// a JSON null does nothing, so a nil set stays nil.
+//
+// Before sets had a MarshalJSON method they were marshalled as JSON
+// objects, e.g., {"1":{},"2":{}}; for backward compatibility such objects
+// are also accepted.
+func (me *Set[T]) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if string(trimmed) == "null" {
+		return nil
+	}
+	var elements []T
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var legacy map[T]struct{}
+		if err := json.Unmarshal(trimmed, &legacy); err != nil {
+			return err
+		}
+		elements = make([]T, 0, len(legacy))
+		for element := range legacy {
+			elements = append(elements, element)
+		}
+	} else if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	if *me == nil {
+		*me = make(Set[T], len(elements))
+	}
+	me.Add(elements...)
+	return nil
+}
+
+// Value returns the set as a JSON array string (see [Set.MarshalJSON]).
+// This makes Set[T] satisfy the database/sql/driver.Valuer interface, so a
+// set can be stored in a text or JSON database column.
+func (me Set[T]) Value() (driver.Value, error) {
+	data, err := me.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan replaces this set's elements with those from src, which must be a
+// JSON array as a string or []byte, or nil (SQL NULL) for an empty set.
+// This makes *Set[T] satisfy the database/sql.Scanner interface.
+func (me *Set[T]) Scan(src any) error {
+	var data []byte
+	switch x := src.(type) {
+	case nil:
+		*me = Set[T]{}
+		return nil
+	case []byte:
+		data = x
+	case string:
+		data = []byte(x)
+	default:
+		return fmt.Errorf("gset: can't scan %T into a set", src)
+	}
+	set := Set[T]{}
+	if err := set.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*me = set
+	return nil
+}
+
+// MarshalYAML returns the set's elements as a slice sorted by <, so that
+// the set is marshalled as a YAML sequence. This satisfies the
+// yaml.Marshaler interface of gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+func (me Set[T]) MarshalYAML() (any, error) {
+	return me.ToSortedSlice(), nil
+}
+
+// UnmarshalYAML adds the elements from a YAML sequence to this set,
+// allocating the set if it is nil. This satisfies the yaml.Unmarshaler
+// interface of gopkg.in/yaml.v2 (which gopkg.in/yaml.v3 also supports).
+func (me *Set[T]) UnmarshalYAML(unmarshal func(any) error) error {
+	var elements []T
+	if err := unmarshal(&elements); err != nil {
+		return err
+	}
+	if *me == nil {
+		*me = make(Set[T], len(elements))
+	}
+	me.Add(elements...)
+	return nil
+}
+
+// ToCSVRecord returns the set's elements sorted by < and formatted using %v
+// (strings are not quoted), suitable for writing as a single record with
+// encoding/csv. See also [SetFromCSVRecord].
+func (me Set[T]) ToCSVRecord() []string {
+	record := make([]string, 0, len(me))
+	for _, element := range me.ToSortedSlice() {
+		record = append(record, fmt.Sprintf("%v", element))
+	}
+	return record
+}
+
+// SetFromCSVRecord returns a new set containing the elements obtained by
+// calling parse on each field of the given CSV record, or the first error
+// that parse returns. See also [Set.ToCSVRecord].
+func SetFromCSVRecord[T comparable](record []string,
+	parse func(string) (T, error)) (Set[T], error) {
+	set := make(Set[T], len(record))
+	for _, field := range record {
+		element, err := parse(field)
+		if err != nil {
+			return nil, err
+		}
+		set[element] = struct{}{}
+	}
+	return set, nil
+}
+
+// MarshalXML writes the set as an XML element with an <item> child element
+// for each of its elements sorted by <, e.g., <set><item>1</item>
+// <item>2</item></set>. The outer element's name is taken from the
+// struct field's tag or name in the normal way, or is "set" if the set is
+// marshalled directly. An empty set gives an element with no children.
+func (me Set[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if start.Name.Local == "" || strings.HasPrefix(start.Name.Local, "Set[") {
+		start.Name.Local = "set"
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	item := xml.StartElement{Name: xml.Name{Local: "item"}}
+	for _, element := range me.ToSortedSlice() {
+		if err := e.EncodeElement(element, item); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML adds the elements from the <item> child elements of an XML
+// element (as written by [Set.MarshalXML]) to this set, allocating the set
+// if it is nil.
+func (me *Set[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var items struct {
+		Items []T `xml:"item"`
+	}
+	if err := d.DecodeElement(&items, &start); err != nil {
+		return err
+	}
+	if *me == nil {
+		*me = make(Set[T], len(items.Items))
+	}
+	me.Add(items.Items...)
+	return nil
+}
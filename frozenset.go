@@ -0,0 +1,81 @@
+// Copyright © 2022 Mark Summerfield. All rights reserved.
+// License: Apache-2.0
+
+package gset
+
+// FrozenSet is a read-only view of a set.
+//
+// A frozen set shares its elements with the set it was made from (no
+// copying is done), but provides no methods for changing them. Note that
+// changes made to the original set are visible through the frozen set.
+//
+// See [Set.Frozen] for how to create a frozen set and [FrozenSet.Thaw] to
+// get a mutable copy.
+type FrozenSet[T comparable] struct {
+	set Set[T]
+}
+
+// Frozen returns a read-only view of this set.
+func (me Set[T]) Frozen() FrozenSet[T] { return FrozenSet[T]{me} }
+
+// Thaw returns a new (mutable) set that is a copy of this frozen set.
+func (me FrozenSet[T]) Thaw() Set[T] { return me.set.Copy() }
+
+// String returns a human readable string representation of the set.
+// See [Set.String].
+func (me FrozenSet[T]) String() string { return me.set.String() }
+
+// ToSlice returns this set's elements as a slice.
+func (me FrozenSet[T]) ToSlice() []T { return me.set.ToSlice() }
+
+// ToSortedSlice returns this set's elements as a slice with the elements
+// sorted using <.
+func (me FrozenSet[T]) ToSortedSlice() []T { return me.set.ToSortedSlice() }
+
+// Len returns the number of elements in the set.
+func (me FrozenSet[T]) Len() int { return len(me.set) }
+
+// IsEmpty returns true if the set is empty; otherwise returns false.
+func (me FrozenSet[T]) IsEmpty() bool { return len(me.set) == 0 }
+
+// Contains returns true if element is in the set; otherwise returns false.
+func (me FrozenSet[T]) Contains(element T) bool {
+	return me.set.Contains(element)
+}
+
+// Difference returns a new set that contains the elements which are in this
+// set that are not in the other set.
+func (me FrozenSet[T]) Difference(other Set[T]) Set[T] {
+	return me.set.Difference(other)
+}
+
+// SymmetricDifference returns a new set that contains the elements which
+// are in this set or the other set—but not in both sets.
+func (me FrozenSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return me.set.SymmetricDifference(other)
+}
+
+// Intersection returns a new set that contains the elements this set has in
+// common with the other set.
+func (me FrozenSet[T]) Intersection(other Set[T]) Set[T] {
+	return me.set.Intersection(other)
+}
+
+// Union returns a new set that contains the elements from this set and from
+// the other set.
+func (me FrozenSet[T]) Union(other Set[T]) Set[T] {
+	return me.set.Union(other)
+}
+
+// Equal returns true if this set has the same elements as the other set;
+// otherwise returns false.
+func (me FrozenSet[T]) Equal(other Set[T]) bool { return me.set.Equal(other) }
+
+// IsDisjoint returns true if this set has no elements in common with the
+// other set; otherwise returns false.
+func (me FrozenSet[T]) IsDisjoint(other Set[T]) bool {
+	return me.set.IsDisjoint(other)
+}
+
+// Hash returns a hash value for this set; see [Set.Hash].
+func (me FrozenSet[T]) Hash() uint64 { return me.set.Hash() }
@@ -1,3 +1,3 @@
 module github.com/mark-summerfield/gset
 
-go 1.19
+go 1.24
@@ -6,13 +6,24 @@
 // Set supports all the map methods, functions that apply to maps (e.g.,
 // len()), and has its own often more convenient API.
 //
+// A nil Set (e.g., var s Set[int]) behaves as an empty set for all the
+// methods that don't change the set, and for those that return a new set.
+//
 // See [New] for how to create empty or populated sets.
 package gset
 
 import (
+	"cmp"
 	_ "embed"
+	"errors"
 	"fmt"
+	"hash/maphash"
+	"io"
+	"iter"
+	"math/rand"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -21,8 +32,59 @@ var Version string // This module's version.
 
 const maxDisplayableElements = 100
 
+// hashSeed is used by [Set.Hash] so that hashes are comparable within a run.
+var hashSeed = maphash.MakeSeed()
+
+// ErrNotPresent is returned by [Set.Remove] if the element to remove isn't
+// in the set.
+var ErrNotPresent = errors.New("gset: element not present")
+
 type Set[T comparable] map[T]struct{}
 
+// Number is a constraint that permits any integer or floating-point type.
+// It is the equivalent of constraints.Integer | constraints.Float from
+// golang.org/x/exp/constraints.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Relation describes how one set relates to another; see [Set.RelationTo].
+type Relation uint8
+
+const (
+	Equal       Relation = iota // Both sets have the same elements
+	Subset                      // The other set has all of this set's and more
+	Superset                    // This set has all of the other's and more
+	Disjoint                    // The sets have no elements in common
+	Overlapping                 // Each set has elements that the other lacks
+)
+
+// String returns the relation's name, e.g., "Subset".
+func (me Relation) String() string {
+	switch me {
+	case Equal:
+		return "Equal"
+	case Subset:
+		return "Subset"
+	case Superset:
+		return "Superset"
+	case Disjoint:
+		return "Disjoint"
+	case Overlapping:
+		return "Overlapping"
+	}
+	return fmt.Sprintf("Relation(%d)", me)
+}
+
+// Pair holds an ordered pair of values; see [CartesianProduct].
+// A Pair is comparable if both A and B are, so can be used as a set element.
+type Pair[A, B comparable] struct {
+	First  A
+	Second B
+}
+
 // New returns a new set containing the given elements (if any).
 // If no elements are given, the type must be specified since it can't be
 // inferred.
@@ -58,11 +120,173 @@ func (me Set[T]) String() string {
 	return s.String()
 }
 
+// WriteTo writes the set's string representation, "{e1 e2 ... eN}" with
+// elements sorted by <, to w, and returns the number of bytes written.
+// Unlike [Set.String], all the elements are written however many there
+// are, and they are written one at a time rather than being built up into
+// a string in memory.
+// For many small writes to be efficient w should be buffered.
+func (me Set[T]) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	n, err := io.WriteString(w, "{")
+	total += int64(n)
+	if err != nil {
+		return total, err
+	}
+	sep := ""
+	for _, element := range me.ToSortedSlice() {
+		if selement, ok := any(element).(string); ok {
+			n, err = fmt.Fprintf(w, "%s%q", sep, selement)
+		} else {
+			n, err = fmt.Fprintf(w, "%s%v", sep, element)
+		}
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+		sep = " "
+	}
+	n, err = io.WriteString(w, "}")
+	total += int64(n)
+	return total, err
+}
+
+// ReadFrom reads the string representation of a set, "{e1 e2 ... eN}" as
+// written by [Set.WriteTo], from r, adds the elements to this set, and
+// returns the number of bytes read. If this set is nil a new set is
+// allocated.
+// Only sets of strings, bools, and integer and floating-point types can be
+// read.
+func (me *Set[T]) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	elements, err := parseElements[T](string(data))
+	if err != nil {
+		return int64(len(data)), err
+	}
+	if *me == nil {
+		*me = make(Set[T], len(elements))
+	}
+	me.Add(elements...)
+	return int64(len(data)), nil
+}
+
+// Set adds the element(s) given in text to this set, allocating the set if
+// it is nil. This makes *Set[T] satisfy the flag.Value interface, so a set
+// can collect a command line flag that is given more than once, e.g.,
+// -tag a -tag b, or with comma-separated elements, e.g., -tag a,b.
+// Surrounding whitespace and empty elements are ignored, and string
+// elements must not be quoted.
+// Only sets of strings, bools, and integer and floating-point types are
+// supported.
+func (me *Set[T]) Set(text string) error {
+	var elements []T
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		element, err := parseElement[T](part)
+		if err != nil {
+			return err
+		}
+		elements = append(elements, element)
+	}
+	if *me == nil {
+		*me = make(Set[T], len(elements))
+	}
+	me.Add(elements...)
+	return nil
+}
+
+// GoString returns a Go source representation of the set, e.g.,
+// "gset.New(1, 2, 3)", with elements sorted by <. This is used by the %#v
+// format verb.
+func (me Set[T]) GoString() string {
+	var s strings.Builder
+	s.WriteString("gset.New")
+	var zero T
+	switch any(zero).(type) {
+	case int, string, bool: // These types are inferred from their values.
+		if len(me) == 0 {
+			fmt.Fprintf(&s, "[%T]", zero)
+		}
+	default:
+		fmt.Fprintf(&s, "[%T]", zero)
+	}
+	s.WriteString("(")
+	sep := ""
+	for _, element := range me.ToSortedSlice() {
+		s.WriteString(sep)
+		fmt.Fprintf(&s, "%#v", element)
+		sep = ", "
+	}
+	s.WriteString(")")
+	return s.String()
+}
+
+// Format implements fmt.Formatter so that sets respect the fmt verbs and
+// flags. %v and %s give the same as [Set.String]; %#v gives the same as
+// [Set.GoString]; and %+v gives each element on its own line. A width may
+// be given for padding, and the - flag left-aligns. %q, %x, and %X format
+// [Set.String]'s result as they would for a string.
+func (me Set[T]) Format(f fmt.State, verb rune) {
+	var text string
+	switch verb {
+	case 'v':
+		if f.Flag('#') {
+			text = me.GoString()
+		} else if f.Flag('+') {
+			text = me.multilineString()
+		} else {
+			text = me.String()
+		}
+	case 's':
+		text = me.String()
+	case 'q', 'x', 'X': // As fmt formats a fmt.Stringer with these verbs.
+		fmt.Fprintf(f, fmt.FormatString(f, verb), me.String())
+		return
+	default:
+		fmt.Fprintf(f, "%%!%c(gset.Set=%s)", verb, me.String())
+		return
+	}
+	if width, ok := f.Width(); ok {
+		if f.Flag('-') {
+			fmt.Fprintf(f, "%-*s", width, text)
+		} else {
+			fmt.Fprintf(f, "%*s", width, text)
+		}
+	} else {
+		fmt.Fprint(f, text)
+	}
+}
+
+func (me Set[T]) multilineString() string {
+	if len(me) == 0 {
+		return "{}"
+	}
+	var s strings.Builder
+	s.WriteString("{\n")
+	for _, element := range me.ToSortedSlice() {
+		if selement, ok := any(element).(string); ok {
+			fmt.Fprintf(&s, "\t%q\n", selement)
+		} else {
+			fmt.Fprintf(&s, "\t%v\n", element)
+		}
+	}
+	s.WriteString("}")
+	return s.String()
+}
+
 func less(a, b any) bool {
 	switch x := a.(type) {
-	case byte:
+	case bool:
+		return !x && b.(bool)
+	case byte: // Same as uint8
 		return x < b.(byte)
-	case rune:
+	case rune: // Same as int32
 		return x < b.(rune)
 	case int8:
 		return x < b.(int8)
@@ -80,10 +304,18 @@ func less(a, b any) bool {
 		return x < b.(uint64)
 	case uint:
 		return x < b.(uint)
+	case uintptr:
+		return x < b.(uintptr)
 	case float32:
 		return x < b.(float32)
 	case float64:
 		return x < b.(float64)
+	case complex64:
+		y := b.(complex64)
+		return real(x) < real(y) || (real(x) == real(y) && imag(x) < imag(y))
+	case complex128:
+		y := b.(complex128)
+		return real(x) < real(y) || (real(x) == real(y) && imag(x) < imag(y))
 	case string:
 		return x < b.(string)
 	default:
@@ -91,6 +323,28 @@ func less(a, b any) bool {
 	}
 }
 
+// Collect returns a new set containing the elements from the given
+// sequence, e.g., gset.Collect(slices.Values(elements)).
+func Collect[T comparable](seq iter.Seq[T]) Set[T] {
+	set := Set[T]{}
+	for element := range seq {
+		set[element] = struct{}{}
+	}
+	return set
+}
+
+// All returns an iterator over this set's elements (in arbitrary order)
+// for use with range-over-func.
+func (me Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for element := range me {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
+
 // ToSlice returns this set's elements as a slice.
 // For iteration either use this, or if you only need one value at a time,
 // use map syntax with a for loop.
@@ -119,18 +373,269 @@ func (me Set[T]) ToSortedSlice() []T {
 	return result
 }
 
-// Add adds the given element(s) to the set.
-func (me Set[T]) Add(elements ...T) {
+// ToSortedSliceDesc returns this set's elements as a slice with the
+// elements sorted in descending order, i.e., the reverse of
+// [Set.ToSortedSlice].
+func (me Set[T]) ToSortedSliceDesc() []T {
+	result := me.ToSlice()
+	sort.Slice(result, func(i, j int) bool {
+		return less(result[j], result[i])
+	})
+	return result
+}
+
+// AppendTo appends this set's elements to dst and returns the extended
+// slice. See also [Set.AppendSortedTo].
+func (me Set[T]) AppendTo(dst []T) []T {
+	for element := range me {
+		dst = append(dst, element)
+	}
+	return dst
+}
+
+// AppendSortedTo appends this set's elements sorted using < to dst and
+// returns the extended slice. (Only the appended elements are sorted.)
+// See also [Set.AppendTo].
+func (me Set[T]) AppendSortedTo(dst []T) []T {
+	start := len(dst)
+	dst = me.AppendTo(dst)
+	appended := dst[start:]
+	sort.Slice(appended, func(i, j int) bool {
+		return less(appended[i], appended[j])
+	})
+	return dst
+}
+
+// ToSortedStrings returns the given set's elements as strings in the same
+// order and format as [Set.String] uses, i.e., sorted using < with string
+// elements quoted and others formatted using %v.
+func ToSortedStrings[T comparable](s Set[T]) []string {
+	result := make([]string, 0, len(s))
+	for _, element := range s.ToSortedSlice() {
+		if selement, ok := any(element).(string); ok {
+			result = append(result, strconv.Quote(selement))
+		} else {
+			result = append(result, fmt.Sprintf("%v", element))
+		}
+	}
+	return result
+}
+
+// ForEachSorted calls fn for each of this set's elements in the order given
+// by [Set.ToSortedSlice], stopping as soon as fn returns false.
+func (me Set[T]) ForEachSorted(fn func(T) bool) {
+	for _, element := range me.ToSortedSlice() {
+		if !fn(element) {
+			return
+		}
+	}
+}
+
+// SortedSlice returns the given set's elements as a slice sorted using <.
+// Unlike [Set.ToSortedSlice] this works correctly for all ordered types,
+// including those with custom names such as type ID int.
+func SortedSlice[T cmp.Ordered](s Set[T]) []T {
+	result := s.ToSlice()
+	slices.Sort(result)
+	return result
+}
+
+// NthSmallest returns the n-th smallest (0-based) element in the given set
+// and true, or the zero value and false if n is out of range. For example,
+// NthSmallest(s, len(s)/2) returns the median (or upper median).
+// This uses quickselect so takes O(n) time on average rather than the
+// O(n log n) needed to sort.
+func NthSmallest[T cmp.Ordered](s Set[T], n int) (T, bool) {
+	if n < 0 || n >= len(s) {
+		var zero T
+		return zero, false
+	}
+	return nth(s.ToSlice(), n, cmp.Less[T]), true
+}
+
+// nth reorders elements and returns the n-th smallest (0-based) according
+// to isLess using quickselect; n must be in range.
+func nth[T any](elements []T, n int, isLess func(a, b T) bool) T {
+	low, high := 0, len(elements)-1
+	for low < high {
+		// Partition around the middle element (Lomuto scheme).
+		mid := low + (high-low)/2
+		elements[mid], elements[high] = elements[high], elements[mid]
+		pivot := elements[high]
+		i := low
+		for j := low; j < high; j++ {
+			if isLess(elements[j], pivot) {
+				elements[i], elements[j] = elements[j], elements[i]
+				i++
+			}
+		}
+		elements[i], elements[high] = elements[high], elements[i]
+		switch {
+		case n < i:
+			high = i - 1
+		case n > i:
+			low = i + 1
+		default:
+			return elements[i]
+		}
+	}
+	return elements[n]
+}
+
+// Add adds the given element(s) to the set and returns how many of them
+// weren't already in the set.
+func (me Set[T]) Add(elements ...T) int {
+	count := 0
 	for _, element := range elements {
-		me[element] = struct{}{}
+		if _, found := me[element]; !found {
+			me[element] = struct{}{}
+			count++
+		}
+	}
+	return count
+}
+
+// AddSlice adds the elements from the given slice to the set and returns
+// how many of them weren't already in the set. This is the same as
+// s.Add(elements...).
+func (me Set[T]) AddSlice(elements []T) int {
+	return me.Add(elements...)
+}
+
+// AddSeq adds the elements from the given sequence to the set and returns
+// how many of them weren't already in the set.
+func (me Set[T]) AddSeq(seq iter.Seq[T]) int {
+	count := 0
+	for element := range seq {
+		if _, found := me[element]; !found {
+			me[element] = struct{}{}
+			count++
+		}
+	}
+	return count
+}
+
+// TryAdd adds the element and returns true if it wasn't already in the set;
+// otherwise does nothing and returns false.
+func (me Set[T]) TryAdd(element T) bool {
+	if _, found := me[element]; found {
+		return false
 	}
+	me[element] = struct{}{}
+	return true
 }
 
-// Delete deletes the given element(s) from the set.
-func (me Set[T]) Delete(elements ...T) {
+// Toggle deletes the element if it is in the set and returns false, or adds
+// it if it isn't and returns true; i.e., returns whether the element is now
+// in the set.
+func (me Set[T]) Toggle(element T) bool {
+	if _, found := me[element]; found {
+		delete(me, element)
+		return false
+	}
+	me[element] = struct{}{}
+	return true
+}
+
+// Grown returns a new set containing this set's elements with room for at
+// least n more elements, so that that many can be added without rehashing.
+// This set is unchanged. (Go maps can't be grown in place; to create an
+// empty set with room for n elements use make(Set[T], n) or [NewCap].)
+func (me Set[T]) Grown(n int) Set[T] {
+	set := make(Set[T], len(me)+max(n, 0))
+	for element := range me {
+		set[element] = struct{}{}
+	}
+	return set
+}
+
+// Delete deletes the given element(s) from the set and returns how many of
+// them were in the set.
+func (me Set[T]) Delete(elements ...T) int {
+	count := 0
 	for _, element := range elements {
+		if _, found := me[element]; found {
+			delete(me, element)
+			count++
+		}
+	}
+	return count
+}
+
+// Discard deletes the element and returns true if it is in the set;
+// otherwise does nothing and returns false. See also [Set.Remove].
+func (me Set[T]) Discard(element T) bool {
+	if _, found := me[element]; found {
 		delete(me, element)
+		return true
 	}
+	return false
+}
+
+// Remove deletes the element if it is in the set; otherwise returns an error
+// that wraps [ErrNotPresent]. See also [Set.Discard].
+func (me Set[T]) Remove(element T) error {
+	if _, found := me[element]; !found {
+		return fmt.Errorf("%w: %v", ErrNotPresent, element)
+	}
+	delete(me, element)
+	return nil
+}
+
+// DeleteAll deletes every element that is in the other set from this set.
+// This is the in-place equivalent of [Set.Difference].
+// See also [Set.DifferenceUpdate].
+func (me Set[T]) DeleteAll(other Set[T]) {
+	if len(other) < len(me) {
+		for element := range other {
+			delete(me, element)
+		}
+	} else {
+		for element := range me {
+			if other.Contains(element) {
+				delete(me, element)
+			}
+		}
+	}
+}
+
+// DifferenceUpdate deletes every element that is in the other set from
+// this set. This is the same as [Set.DeleteAll].
+func (me Set[T]) DifferenceUpdate(other Set[T]) { me.DeleteAll(other) }
+
+// PopFunc deletes an element for which pred returns true and returns it and
+// true, or returns the zero value and false if there is no such element.
+// If more than one element matches, which one is deleted is arbitrary.
+func (me Set[T]) PopFunc(pred func(T) bool) (T, bool) {
+	for element := range me {
+		if pred(element) {
+			delete(me, element)
+			return element, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// TakeN deletes up to n arbitrary elements and returns them. If n >=
+// len(s) all the elements are deleted and returned; if n <= 0 nothing is
+// deleted and an empty slice is returned.
+func (me Set[T]) TakeN(n int) []T {
+	if n <= 0 {
+		return []T{}
+	}
+	if n > len(me) {
+		n = len(me)
+	}
+	taken := make([]T, 0, n)
+	for element := range me {
+		if len(taken) == n {
+			break
+		}
+		taken = append(taken, element)
+	}
+	me.Delete(taken...)
+	return taken
 }
 
 // Clear deletes all the elements to make this an empty set.
@@ -144,6 +649,26 @@ func (me Set[T]) Clear() {
 // This is just a convenience for len(s) == 0.
 func (me Set[T]) IsEmpty() bool { return len(me) == 0 }
 
+// Len returns the number of elements in the set.
+// This is just a convenience for len(s).
+func (me Set[T]) Len() int { return len(me) }
+
+// IsSingleton returns true if the set has exactly one element; otherwise
+// returns false.
+func (me Set[T]) IsSingleton() bool { return len(me) == 1 }
+
+// Single returns the set's element and true if the set has exactly one
+// element; otherwise returns the zero value and false.
+func (me Set[T]) Single() (T, bool) {
+	if len(me) == 1 {
+		for element := range me {
+			return element, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
 // Contains returns true if element is in the set; otherwise returns false.
 // Alternatively, use map syntax.
 func (me Set[T]) Contains(element T) bool {
@@ -151,6 +676,161 @@ func (me Set[T]) Contains(element T) bool {
 	return found
 }
 
+// ContainsAll returns true if every one of the given elements is in the set;
+// otherwise returns false. Returns true if no elements are given.
+func (me Set[T]) ContainsAll(elements ...T) bool {
+	for _, element := range elements {
+		if _, found := me[element]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny returns true if at least one of the given elements is in the
+// set; otherwise returns false. Returns false if no elements are given.
+func (me Set[T]) ContainsAny(elements ...T) bool {
+	for _, element := range elements {
+		if _, found := me[element]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// FindFunc returns an element for which pred returns true and true, or the
+// zero value and false if there is no such element. If more than one
+// element matches, which one is returned is arbitrary and may differ from
+// call to call.
+func (me Set[T]) FindFunc(pred func(T) bool) (T, bool) {
+	for element := range me {
+		if pred(element) {
+			return element, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Satisfies folds this set's elements into a bool: starting with initial
+// as the accumulator, it calls fn(accumulator, element) for each element
+// (in an arbitrary order) and makes the result the new accumulator. As soon
+// as fn returns stopOn, Satisfies returns stopOn without calling fn for the
+// remaining elements; otherwise it returns the final accumulator (which is
+// initial if the set is empty). Note that initial itself is not compared
+// with stopOn, so fn is always called at least once for a nonempty set.
+//
+// For example, "every element matches" is Satisfies(true, func(acc bool,
+// x T) bool { return acc && pred(x) }, false), and "any element matches"
+// is Satisfies(false, func(acc bool, x T) bool { return acc || pred(x) },
+// true).
+func (me Set[T]) Satisfies(initial bool, fn func(bool, T) bool,
+	stopOn bool) bool {
+	accumulator := initial
+	for element := range me {
+		if accumulator = fn(accumulator, element); accumulator == stopOn {
+			return stopOn
+		}
+	}
+	return accumulator
+}
+
+// Filter returns a new set containing this set's elements for which pred
+// returns true. See also [Set.FilterInto].
+func (me Set[T]) Filter(pred func(T) bool) Set[T] {
+	result := Set[T]{}
+	me.FilterInto(pred, result)
+	return result
+}
+
+// FilterInto clears dst and then adds this set's elements for which pred
+// returns true to it. This is the same as [Set.Filter] but reuses dst
+// (and its capacity) rather than creating a new set. dst must not be nil
+// and must not be the same set as this set.
+func (me Set[T]) FilterInto(pred func(T) bool, dst Set[T]) {
+	clear(dst)
+	for element := range me {
+		if pred(element) {
+			dst[element] = struct{}{}
+		}
+	}
+}
+
+// MapInPlace replaces each of this set's elements with fn(element). If fn
+// maps two or more elements to the same value, the set will contain that
+// value only once, so the set may shrink. See also [Map].
+func (me Set[T]) MapInPlace(fn func(T) T) {
+	elements := make([]T, 0, len(me))
+	for element := range me {
+		elements = append(elements, fn(element))
+	}
+	clear(me)
+	for _, element := range elements {
+		me[element] = struct{}{}
+	}
+}
+
+// RandomElement returns a randomly chosen element and true, or the zero
+// value and false if the set is empty. Every element has an equal chance of
+// being chosen (unlike taking the first element from a range over the set).
+// So that the same sequence of random numbers always gives the same choices
+// (e.g., when testing), this chooses a random index k and returns the k-th
+// smallest element by the order [Set.ToSortedSlice] uses, which takes O(n)
+// time on average. (Elements of types other than strings, bools, and
+// numbers are ordered by their %v text, so each comparison costs two
+// fmt.Sprintf calls.)
+func (me Set[T]) RandomElement(r *rand.Rand) (T, bool) {
+	if len(me) == 0 {
+		var zero T
+		return zero, false
+	}
+	return nth(me.ToSlice(), r.Intn(len(me)), func(a, b T) bool {
+		return less(a, b)
+	}), true
+}
+
+// Sample returns up to n distinct randomly chosen elements in random order.
+// If n >= len(s) all the elements are returned (shuffled); if n <= 0 an
+// empty slice is returned. Every element has an equal chance of being
+// chosen. So that the same sequence of random numbers always gives the
+// same result, the elements are sorted (as by [Set.ToSortedSlice]) before
+// choosing, so this takes O(n log n) time however small n is, and even
+// longer for elements ordered by their %v text (see [Set.RandomElement]).
+func (me Set[T]) Sample(n int, r *rand.Rand) []T {
+	if n <= 0 {
+		return []T{}
+	}
+	elements := me.ToSortedSlice()
+	if n > len(elements) {
+		n = len(elements)
+	}
+	// Partial Fisher–Yates: only the first n positions need to be chosen.
+	for i := 0; i < n; i++ {
+		j := i + r.Intn(len(elements)-i)
+		elements[i], elements[j] = elements[j], elements[i]
+	}
+	return elements[:n]
+}
+
+// SampleSeed returns up to n distinct randomly chosen elements in random
+// order using random numbers seeded with seed. This is the same as
+// s.Sample(n, rand.New(rand.NewSource(seed))).
+func (me Set[T]) SampleSeed(n int, seed int64) []T {
+	return me.Sample(n, rand.New(rand.NewSource(seed)))
+}
+
+// ShuffledSlice returns this set's elements as a slice in random order.
+// Every order is equally likely. As with [Set.Sample], the elements are
+// sorted before shuffling, so that the same sequence of random numbers
+// always gives the same result, which takes O(n log n) time.
+func (me Set[T]) ShuffledSlice(r *rand.Rand) []T {
+	elements := me.ToSortedSlice()
+	r.Shuffle(len(elements), func(i, j int) {
+		elements[i], elements[j] = elements[j], elements[i]
+	})
+	return elements
+}
+
 // Difference returns a new set that contains the elements which are in this
 // set that are not in the other set.
 func (me Set[T]) Difference(other Set[T]) Set[T] {
@@ -163,6 +843,60 @@ func (me Set[T]) Difference(other Set[T]) Set[T] {
 	return diff
 }
 
+// Complement returns a new set that contains the elements of universe that
+// are not in this set. Any elements of this set that aren't in universe are
+// ignored. This is the same as universe.Difference(s).
+func (me Set[T]) Complement(universe Set[T]) Set[T] {
+	return universe.Difference(me)
+}
+
+// DifferenceInto clears dst and then adds the elements which are in this
+// set that are not in the other set to it. This is the same as
+// [Set.Difference] but reuses dst rather than creating a new set. dst must
+// not be nil and must not be the same set as either this set or the other
+// set.
+func (me Set[T]) DifferenceInto(other, dst Set[T]) {
+	clear(dst)
+	for element := range me {
+		if !other.Contains(element) {
+			dst[element] = struct{}{}
+		}
+	}
+}
+
+// DifferenceCardinality returns how many elements are in this set that are
+// not in the other set. This is the same as len(s.Difference(other)) but
+// doesn't create a new set.
+func (me Set[T]) DifferenceCardinality(other Set[T]) int {
+	count := 0
+	for element := range me {
+		if !other.Contains(element) {
+			count++
+		}
+	}
+	return count
+}
+
+// Diff returns the changes from this set to the other set as two new sets:
+// added contains the elements which are in the other set but not in this
+// one, and removed contains the elements which are in this set but not in
+// the other one.
+func (me Set[T]) Diff(other Set[T]) (added, removed Set[T]) {
+	added = Set[T]{}
+	removed = Set[T]{}
+	for element := range me {
+		if !other.Contains(element) {
+			removed[element] = struct{}{}
+		}
+	}
+	for element := range other {
+		if !me.Contains(element) {
+			added[element] = struct{}{}
+		}
+	}
+	return added, removed
+}
+
 // SymmetricDifference returns a new set that contains the elements which
 // are in this set or the other set—but not in both sets.
 func (me Set[T]) SymmetricDifference(other Set[T]) Set[T] {
@@ -180,44 +914,136 @@ func (me Set[T]) SymmetricDifference(other Set[T]) Set[T] {
 	return diff
 }
 
+// SymmetricDifferenceInto clears dst and then adds the elements which are
+// in this set or the other set—but not in both sets—to it. This is the same
+// as [Set.SymmetricDifference] but reuses dst rather than creating a new
+// set. dst must not be nil and must not be the same set as either this set
+// or the other set.
+func (me Set[T]) SymmetricDifferenceInto(other, dst Set[T]) {
+	clear(dst)
+	for element := range me {
+		if !other.Contains(element) {
+			dst[element] = struct{}{}
+		}
+	}
+	for element := range other {
+		if !me.Contains(element) {
+			dst[element] = struct{}{}
+		}
+	}
+}
+
+// SymmetricDifferenceCardinality returns how many elements are in this set
+// or the other set—but not in both sets. This is the same as
+// len(s.SymmetricDifference(other)), i.e., the Hamming distance between
+// the sets, but doesn't create a new set.
+func (me Set[T]) SymmetricDifferenceCardinality(other Set[T]) int {
+	return len(me) + len(other) - 2*me.IntersectionCardinality(other)
+}
+
 // Intersection returns a new set that contains the elements this set has in
 // common with the other set.
 func (me Set[T]) Intersection(other Set[T]) Set[T] {
+	smaller, larger := me, other
+	if len(smaller) > len(larger) {
+		smaller, larger = larger, smaller
+	}
 	intersection := Set[T]{}
-	for element := range me {
-		if other.Contains(element) {
+	for element := range smaller {
+		if larger.Contains(element) {
 			intersection[element] = struct{}{}
 		}
 	}
-	for element := range other {
-		if me.Contains(element) {
-			intersection[element] = struct{}{}
+	return intersection
+}
+
+// IntersectionInto clears dst and then adds the elements this set has in
+// common with the other set to it. This is the same as [Set.Intersection]
+// but reuses dst rather than creating a new set. dst must not be nil and
+// must not be the same set as either this set or the other set.
+func (me Set[T]) IntersectionInto(other, dst Set[T]) {
+	clear(dst)
+	smaller, larger := me, other
+	if len(smaller) > len(larger) {
+		smaller, larger = larger, smaller
+	}
+	for element := range smaller {
+		if larger.Contains(element) {
+			dst[element] = struct{}{}
 		}
 	}
-	return intersection
+}
+
+// IntersectionCardinality returns how many elements this set has in common
+// with the other set. This is the same as len(s.Intersection(other)) but
+// doesn't create a new set.
+func (me Set[T]) IntersectionCardinality(other Set[T]) int {
+	smaller, larger := me, other
+	if len(smaller) > len(larger) {
+		smaller, larger = larger, smaller
+	}
+	count := 0
+	for element := range smaller {
+		if larger.Contains(element) {
+			count++
+		}
+	}
+	return count
 }
 
 // Union returns a new set that contains the elements from this set and from
 // the other set (with no duplicates of course).
 // See also [Set.Unite].
 func (me Set[T]) Union(other Set[T]) Set[T] {
-	union := make(Set[T], len(me))
-	for element := range me {
+	smaller, larger := me, other
+	if len(smaller) > len(larger) {
+		smaller, larger = larger, smaller
+	}
+	union := make(Set[T], len(larger))
+	for element := range larger {
 		union[element] = struct{}{}
 	}
-	for element := range other {
+	for element := range smaller {
 		union[element] = struct{}{}
 	}
 	return union
 }
 
-// Unite adds all the elements from other that aren't already in this set to
-// this set.
-// See also [Set.Union].
-func (me Set[T]) Unite(other Set[T]) {
+// UnionInto clears dst and then adds the elements from this set and from
+// the other set to it. This is the same as [Set.Union] but reuses dst
+// rather than creating a new set. dst must not be nil and must not be the
+// same set as either this set or the other set.
+func (me Set[T]) UnionInto(other, dst Set[T]) {
+	clear(dst)
+	for element := range me {
+		dst[element] = struct{}{}
+	}
 	for element := range other {
-		me[element] = struct{}{}
+		dst[element] = struct{}{}
+	}
+}
+
+// UnionCardinality returns how many elements are in this set or the other
+// set or both. This is the same as len(s.Union(other)) but doesn't create a
+// new set.
+func (me Set[T]) UnionCardinality(other Set[T]) int {
+	return len(me) + len(other) - me.IntersectionCardinality(other)
+}
+
+// Unite adds all the elements from the other set(s) that aren't already in
+// this set to this set and returns how many were added.
+// See also [Set.Union].
+func (me Set[T]) Unite(others ...Set[T]) int {
+	count := 0
+	for _, other := range others {
+		for element := range other {
+			if _, found := me[element]; !found {
+				me[element] = struct{}{}
+				count++
+			}
+		}
 	}
+	return count
 }
 
 // Copy returns a copy of this set.
@@ -245,18 +1071,653 @@ func (me Set[T]) Equal(other Set[T]) bool {
 	return true
 }
 
+// EqualSlice returns true if this set has exactly the same elements as the
+// given slice (ignoring order and duplicates); otherwise returns false.
+func (me Set[T]) EqualSlice(items []T) bool {
+	seen := make(Set[T], len(me))
+	for _, item := range items {
+		if _, found := me[item]; !found {
+			return false
+		}
+		seen[item] = struct{}{}
+	}
+	return len(seen) == len(me)
+}
+
+// EqualFunc returns true if sets a and b are the same size and every
+// element of a can be paired with a different element of b for which eq
+// returns true; otherwise returns false.
+// This calls eq for every pair of elements (so is O(n²) or worse), and
+// should only be used when elements must be compared in a way other than
+// ==, e.g., ignoring some struct fields.
+func EqualFunc[T comparable](a, b Set[T], eq func(x, y T) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	xs := a.ToSlice()
+	ys := b.ToSlice()
+	matches := make([][]int, len(xs)) // matches[i] = js where eq(xs[i], ys[j])
+	for i, x := range xs {
+		for j, y := range ys {
+			if eq(x, y) {
+				matches[i] = append(matches[i], j)
+			}
+		}
+		if len(matches[i]) == 0 {
+			return false
+		}
+	}
+	// Find a perfect matching using augmenting paths.
+	pairedWith := make([]int, len(ys)) // pairedWith[j] = i or -1
+	for j := range pairedWith {
+		pairedWith[j] = -1
+	}
+	var pair func(i int, seen []bool) bool
+	pair = func(i int, seen []bool) bool {
+		for _, j := range matches[i] {
+			if !seen[j] {
+				seen[j] = true
+				if pairedWith[j] < 0 || pair(pairedWith[j], seen) {
+					pairedWith[j] = i
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for i := range xs {
+		if !pair(i, make([]bool, len(ys))) {
+			return false
+		}
+	}
+	return true
+}
+
+// RelationTo returns how this set relates to the other set, computed in a
+// single pass. The first of these that applies is returned: [Equal] (which
+// includes two empty sets), [Subset] (which includes an empty set compared
+// with a nonempty one), [Superset], [Disjoint], or [Overlapping].
+func (me Set[T]) RelationTo(other Set[T]) Relation {
+	both := me.IntersectionCardinality(other)
+	onlyMe := len(me) - both
+	onlyOther := len(other) - both
+	switch {
+	case onlyMe == 0 && onlyOther == 0:
+		return Equal
+	case onlyMe == 0:
+		return Subset
+	case onlyOther == 0:
+		return Superset
+	case both == 0:
+		return Disjoint
+	}
+	return Overlapping
+}
+
+// SubsetOfAny returns true if every element of this set is in at least one
+// of the others; otherwise returns false. It stops at the first of the
+// others that contains this set. An empty set is a subset of any set, but
+// not of "any" of no sets at all.
+func (me Set[T]) SubsetOfAny(others ...Set[T]) bool {
+	for _, other := range others {
+		if me.isSubsetOf(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// SupersetOfAll returns true if this set contains every element of every
+// one of the others (i.e., their union); otherwise returns false. It stops
+// at the first of the others that has an element this set lacks. Any set
+// is a superset of all of no sets at all.
+func (me Set[T]) SupersetOfAll(others ...Set[T]) bool {
+	for _, other := range others {
+		if !other.isSubsetOf(me) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSubsetOf returns true if every element of this set is in the other set.
+func (me Set[T]) isSubsetOf(other Set[T]) bool {
+	if len(me) > len(other) {
+		return false
+	}
+	for element := range me {
+		if !other.Contains(element) {
+			return false
+		}
+	}
+	return true
+}
+
+// Jaccard returns the Jaccard index of this set and the other set, i.e.,
+// |A∩B| / |A∪B|, a similarity measure in [0, 1]. Returns 1 if both sets
+// are empty. See also [Set.JaccardDistance] and [Set.Tversky].
+func (me Set[T]) Jaccard(other Set[T]) float64 {
+	both := me.IntersectionCardinality(other)
+	union := len(me) + len(other) - both
+	if union == 0 {
+		return 1
+	}
+	return float64(both) / float64(union)
+}
+
+// JaccardDistance returns 1 - [Set.Jaccard], a metric in [0, 1] suitable
+// for use as the distance function when clustering sets. Returns 0 if both
+// sets are empty.
+func (me Set[T]) JaccardDistance(other Set[T]) float64 {
+	return 1 - me.Jaccard(other)
+}
+
+// Tversky returns the Tversky index of this set (A) and the other set (B),
+// i.e., |A∩B| / (|A∩B| + α|A\B| + β|B\A|), a similarity measure in [0, 1]
+// for non-negative alpha and beta. With α = β = 1 this is the Jaccard index
+// and with α = β = 0.5 it is the Dice coefficient; using different values
+// weights the elements that only one set has asymmetrically.
+// If the denominator is 0, returns 1 if both sets are empty and 0
+// otherwise.
+func (me Set[T]) Tversky(other Set[T], alpha, beta float64) float64 {
+	both := me.IntersectionCardinality(other)
+	onlyMe := len(me) - both
+	onlyOther := len(other) - both
+	denominator := float64(both) + alpha*float64(onlyMe) +
+		beta*float64(onlyOther)
+	if denominator == 0 {
+		if len(me) == 0 && len(other) == 0 {
+			return 1
+		}
+		return 0
+	}
+	return float64(both) / denominator
+}
+
+// Hash returns a hash value for this set which doesn't depend on the order
+// of iteration, so equal sets always produce equal hashes. Elements are
+// hashed consistently with ==, so, e.g., 0 and -0 (including inside complex
+// numbers and structs) hash the same.
+// Sets can't be used as map keys, but this makes it possible to bucket sets
+// (e.g., in a map[uint64][]Set[T]) to find duplicates. Since different sets
+// may have the same hash, use [Set.Equal] to confirm that sets are equal.
+// Hash values are only consistent within a single run of a program, so
+// shouldn't be stored.
+func (me Set[T]) Hash() uint64 {
+	var hash uint64
+	for element := range me {
+		hash += maphash.Comparable(hashSeed, element)
+	}
+	return hash
+}
+
 // IsDisjoint returns true if this set has no elements in common with the
 // other set; otherwise returns false.
 func (me Set[T]) IsDisjoint(other Set[T]) bool {
-	for element := range me {
-		if other.Contains(element) {
+	smaller, larger := me, other
+	if len(smaller) > len(larger) {
+		smaller, larger = larger, smaller
+	}
+	for element := range smaller {
+		if larger.Contains(element) {
 			return false
 		}
 	}
-	for element := range other {
-		if me.Contains(element) {
+	return true
+}
+
+// Intersects returns true if this set has at least one element in common
+// with the other set; otherwise returns false.
+// This is the same as !s.IsDisjoint(other).
+func (me Set[T]) Intersects(other Set[T]) bool { return !me.IsDisjoint(other) }
+
+// PowerSet returns every subset of this set (including the empty set and a
+// copy of this set itself), each as a new independent set.
+// The number of subsets is 2^n where n is len(s), so this is only practical
+// for small sets. Panics if len(s) > 63.
+func (me Set[T]) PowerSet() []Set[T] {
+	if len(me) > 63 {
+		panic(fmt.Sprintf("gset: PowerSet of %d elements is too large",
+			len(me)))
+	}
+	elements := me.ToSlice()
+	count := uint64(1) << len(elements)
+	result := make([]Set[T], 0, count)
+	for mask := uint64(0); mask < count; mask++ {
+		subset := Set[T]{}
+		for i, element := range elements {
+			if mask&(uint64(1)<<i) != 0 {
+				subset[element] = struct{}{}
+			}
+		}
+		result = append(result, subset)
+	}
+	return result
+}
+
+// Combinations returns every k-element subset of this set, each as a
+// slice. The elements are sorted by < first so the result is deterministic.
+// If k is 0 the result is a single empty combination; if k is negative or
+// greater than len(s) the result is empty.
+// The number of combinations is n!/(k!(n-k)!) where n is len(s), which
+// grows very quickly, so this is only practical for small sets or for k
+// close to 0 or to n. See also [Set.CombinationsSeq].
+func (me Set[T]) Combinations(k int) [][]T {
+	result := [][]T{}
+	for combination := range me.CombinationsSeq(k) {
+		result = append(result, combination)
+	}
+	return result
+}
+
+// CombinationsSeq returns an iterator over every k-element subset of this
+// set, each as a new slice (so it may be kept). The combinations are
+// produced lazily in the same order as [Set.Combinations] returns them, so
+// breaking out of a range over the iterator avoids creating the rest.
+func (me Set[T]) CombinationsSeq(k int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if k < 0 || k > len(me) {
+			return
+		}
+		elements := me.ToSortedSlice()
+		indexes := make([]int, k)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		for {
+			combination := make([]T, k)
+			for i, index := range indexes {
+				combination[i] = elements[index]
+			}
+			if !yield(combination) {
+				return
+			}
+			// Advance the rightmost index that can move, then reset those
+			// after it.
+			i := k - 1
+			for i >= 0 && indexes[i] == len(elements)-k+i {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			indexes[i]++
+			for j := i + 1; j < k; j++ {
+				indexes[j] = indexes[j-1] + 1
+			}
+		}
+	}
+}
+
+// Permutations returns an iterator over every ordering of this set's
+// elements, each as a new slice (so it may be kept). The elements are
+// sorted by < first, and the orderings are produced lazily in
+// lexicographic order, starting with the sorted order. An empty set has a
+// single (empty) ordering.
+// The number of orderings is n! where n is len(s), so consuming them all is
+// only practical for very small sets.
+func (me Set[T]) Permutations() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		elements := me.ToSortedSlice()
+		indexes := make([]int, len(elements))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		for {
+			permutation := make([]T, len(elements))
+			for i, index := range indexes {
+				permutation[i] = elements[index]
+			}
+			if !yield(permutation) {
+				return
+			}
+			// Advance to the next permutation of indexes in lexicographic
+			// order.
+			i := len(indexes) - 2
+			for i >= 0 && indexes[i] > indexes[i+1] {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			j := len(indexes) - 1
+			for indexes[j] < indexes[i] {
+				j--
+			}
+			indexes[i], indexes[j] = indexes[j], indexes[i]
+			slices.Reverse(indexes[i+1:])
+		}
+	}
+}
+
+// Chunk returns this set's elements sorted by < and split into slices of
+// size elements each, except for the last which may have fewer.
+// Panics if size <= 0.
+func (me Set[T]) Chunk(size int) [][]T {
+	if size <= 0 {
+		panic(fmt.Sprintf("gset: Chunk size must be positive, got %d", size))
+	}
+	elements := me.ToSortedSlice()
+	chunks := make([][]T, 0, (len(elements)+size-1)/size)
+	for len(elements) > size {
+		chunks = append(chunks, elements[:size:size])
+		elements = elements[size:]
+	}
+	if len(elements) > 0 {
+		chunks = append(chunks, elements)
+	}
+	return chunks
+}
+
+// PartitionN returns k new sets between which this set's elements are
+// shared out round-robin (in the order given by [Set.ToSortedSlice]), so
+// the sets' sizes differ by at most one. Panics if k <= 0.
+func (me Set[T]) PartitionN(k int) []Set[T] {
+	if k <= 0 {
+		panic(fmt.Sprintf("gset: PartitionN k must be positive, got %d", k))
+	}
+	parts := make([]Set[T], k)
+	for i := range parts {
+		parts[i] = make(Set[T], (len(me)+k-1)/k)
+	}
+	for i, element := range me.ToSortedSlice() {
+		parts[i%k][element] = struct{}{}
+	}
+	return parts
+}
+
+// CartesianProduct returns a new set containing every ordered pair whose
+// First is from a and whose Second is from b.
+func CartesianProduct[A, B comparable](a Set[A], b Set[B]) Set[Pair[A, B]] {
+	product := make(Set[Pair[A, B]], len(a)*len(b))
+	for first := range a {
+		for second := range b {
+			product[Pair[A, B]{first, second}] = struct{}{}
+		}
+	}
+	return product
+}
+
+// CartesianProductN returns an iterator over every tuple (as a new slice)
+// that has one element from each of the given sets, in the order the sets
+// are given. Each set's elements are sorted by < first, and the tuples are
+// produced lazily in odometer order, i.e., with the last position changing
+// fastest. There are no tuples if any set is empty, and a single empty
+// tuple if no sets are given. See also [CartesianProduct].
+func CartesianProductN[T comparable](sets ...Set[T]) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		columns := make([][]T, len(sets))
+		for i, set := range sets {
+			if len(set) == 0 {
+				return
+			}
+			columns[i] = set.ToSortedSlice()
+		}
+		indexes := make([]int, len(sets))
+		for {
+			tuple := make([]T, len(sets))
+			for i, index := range indexes {
+				tuple[i] = columns[i][index]
+			}
+			if !yield(tuple) {
+				return
+			}
+			i := len(indexes) - 1
+			for ; i >= 0; i-- {
+				indexes[i]++
+				if indexes[i] < len(columns[i]) {
+					break
+				}
+				indexes[i] = 0
+			}
+			if i < 0 {
+				return
+			}
+		}
+	}
+}
+
+// Zip returns a slice of pairs where the i-th pair holds the i-th element
+// of a and the i-th element of b. Since sets are unordered, both sets'
+// elements are sorted by < first so that the result is deterministic. The
+// slice has as many pairs as the smaller set has elements.
+func Zip[A, B comparable](a Set[A], b Set[B]) []Pair[A, B] {
+	firsts := a.ToSortedSlice()
+	seconds := b.ToSortedSlice()
+	size := len(firsts)
+	if len(seconds) < size {
+		size = len(seconds)
+	}
+	pairs := make([]Pair[A, B], 0, size)
+	for i := 0; i < size; i++ {
+		pairs = append(pairs, Pair[A, B]{firsts[i], seconds[i]})
+	}
+	return pairs
+}
+
+// Map returns a new set containing fn(element) for every element in s.
+// If fn maps two or more elements to the same value, the new set will
+// contain that value only once, so may have fewer elements than s.
+// See also [Convert].
+func Map[T, U comparable](s Set[T], fn func(T) U) Set[U] {
+	result := make(Set[U], len(s))
+	for element := range s {
+		result[fn(element)] = struct{}{}
+	}
+	return result
+}
+
+// Convert returns a new set containing the elements of s converted to type
+// U using fn, e.g., Convert(s, func(x int) int64 { return int64(x) }).
+// This is the same as [Map] but intended for lossless conversions; if fn
+// maps two or more elements to the same value, the new set will contain
+// that value only once.
+func Convert[T, U comparable](s Set[T], fn func(T) U) Set[U] {
+	return Map(s, fn)
+}
+
+// MapToSlice returns a new slice containing fn(element) for every element
+// in s (in arbitrary order). Unlike [Map], duplicates are kept and U need
+// not be comparable.
+func MapToSlice[T comparable, U any](s Set[T], fn func(T) U) []U {
+	result := make([]U, 0, len(s))
+	for element := range s {
+		result = append(result, fn(element))
+	}
+	return result
+}
+
+// ToMap returns a new map whose keys are the given set's elements and whose
+// values are the result of calling value on each key.
+func ToMap[T comparable, V any](s Set[T], value func(T) V) map[T]V {
+	result := make(map[T]V, len(s))
+	for element := range s {
+		result[element] = value(element)
+	}
+	return result
+}
+
+// Flatten returns a new set that contains the elements from all the given
+// sets (with no duplicates of course). The new set is empty if sets is.
+func Flatten[T comparable](sets []Set[T]) Set[T] {
+	size := 0
+	for _, set := range sets {
+		size += len(set)
+	}
+	result := make(Set[T], size)
+	for _, set := range sets {
+		for element := range set {
+			result[element] = struct{}{}
+		}
+	}
+	return result
+}
+
+// GroupBy returns a map whose values are new sets which partition the given
+// set's elements, each keyed by the result of calling key on its elements.
+// For example, grouping integers by x%2 gives a set of even numbers keyed
+// by 0 and a set of odd numbers keyed by 1.
+func GroupBy[T, K comparable](s Set[T], key func(T) K) map[K]Set[T] {
+	groups := map[K]Set[T]{}
+	for element := range s {
+		k := key(element)
+		group, ok := groups[k]
+		if !ok {
+			group = Set[T]{}
+			groups[k] = group
+		}
+		group[element] = struct{}{}
+	}
+	return groups
+}
+
+// Sum returns the sum of the given set's elements, or 0 if it is empty.
+// As with normal Go arithmetic, integer sums that overflow wrap around.
+func Sum[T Number](s Set[T]) T {
+	var total T
+	for element := range s {
+		total += element
+	}
+	return total
+}
+
+// AllDisjoint returns true if no two of the given sets have any element in
+// common; otherwise returns false. This takes O(n) time where n is the total
+// number of elements, and stops at the first element found to be shared.
+func AllDisjoint[T comparable](sets ...Set[T]) bool {
+	seen := Set[T]{}
+	for _, set := range sets {
+		for element := range set {
+			if _, found := seen[element]; found {
+				return false
+			}
+			seen[element] = struct{}{}
+		}
+	}
+	return true
+}
+
+// Covers returns true if every element of universe is in at least one of
+// the parts; otherwise returns false.
+func Covers[T comparable](universe Set[T], parts ...Set[T]) bool {
+	for element := range universe {
+		found := false
+		for _, part := range parts {
+			if part.Contains(element) {
+				found = true
+				break
+			}
+		}
+		if !found {
 			return false
 		}
 	}
 	return true
 }
+
+// IntersectionMatrix returns an n×n matrix (where n is len(sets)) whose
+// [i][j] entry is the number of elements sets[i] and sets[j] have in
+// common. The matrix is symmetric and its diagonal holds each set's size.
+func IntersectionMatrix[T comparable](sets []Set[T]) [][]int {
+	matrix := make([][]int, len(sets))
+	for i := range matrix {
+		matrix[i] = make([]int, len(sets))
+	}
+	for i, set := range sets {
+		matrix[i][i] = len(set)
+		for j := i + 1; j < len(sets); j++ {
+			count := set.IntersectionCardinality(sets[j])
+			matrix[i][j] = count
+			matrix[j][i] = count
+		}
+	}
+	return matrix
+}
+
+// Dedup returns a new slice containing the given items with duplicates
+// removed, keeping the first occurrence of each. See also [DedupSorted].
+func Dedup[T comparable](items []T) []T {
+	seen := make(Set[T], len(items))
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if seen.TryAdd(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// DedupSorted returns a new slice containing the given items with
+// duplicates removed, sorted as by [Set.ToSortedSlice]. See also [Dedup].
+func DedupSorted[T comparable](items []T) []T {
+	return New(items...).ToSortedSlice()
+}
+
+// Counter returns a map of each distinct item to the number of times it
+// occurs in items.
+func Counter[T comparable](items []T) map[T]int {
+	counts := map[T]int{}
+	for _, item := range items {
+		counts[item]++
+	}
+	return counts
+}
+
+// UniqueCount returns the number of distinct items; if this is less than
+// len(items), items contains duplicates.
+func UniqueCount[T comparable](items []T) int {
+	return len(New(items...))
+}
+
+// SumBy returns the sum of value(element) for every element in the given
+// set, or 0 if it is empty. As with [Sum], integer sums that overflow wrap
+// around.
+func SumBy[T comparable, N Number](s Set[T], value func(T) N) N {
+	var total N
+	for element := range s {
+		total += value(element)
+	}
+	return total
+}
+
+// CountBy returns a map of each distinct key(element) to how many of the
+// given set's elements have that key. See also [GroupBy].
+func CountBy[T, K comparable](s Set[T], key func(T) K) map[K]int {
+	counts := map[K]int{}
+	for element := range s {
+		counts[key(element)]++
+	}
+	return counts
+}
+
+// MinBy returns the element of the given set for which key returns the
+// smallest value and true, or the zero value and false if the set is empty.
+// If more than one element has the smallest key, which of them is returned
+// is arbitrary. See also [MaxBy].
+func MinBy[T comparable, K cmp.Ordered](s Set[T], key func(T) K) (T, bool) {
+	var result T
+	var resultKey K
+	found := false
+	for element := range s {
+		if k := key(element); !found || k < resultKey {
+			result, resultKey, found = element, k, true
+		}
+	}
+	return result, found
+}
+
+// MaxBy returns the element of the given set for which key returns the
+// largest value and true, or the zero value and false if the set is empty.
+// If more than one element has the largest key, which of them is returned
+// is arbitrary. See also [MinBy].
+func MaxBy[T comparable, K cmp.Ordered](s Set[T], key func(T) K) (T, bool) {
+	var result T
+	var resultKey K
+	found := false
+	for element := range s {
+		if k := key(element); !found || k > resultKey {
+			result, resultKey, found = element, k, true
+		}
+	}
+	return result, found
+}
@@ -1,8 +1,22 @@
 package gset
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -36,6 +50,171 @@ func TestNew(t *testing.T) {
 		t)
 }
 
+func TestWriteTo(t *testing.T) {
+	s := New(3, 1, 2)
+	var out strings.Builder
+	n, err := s.WriteTo(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(out.String(), int(n), "{1 2 3}", 7, t)
+	u := New[int]()
+	for i := 0; i < 111; i++ {
+		u.Add(i)
+	}
+	out.Reset()
+	n, err = u.WriteTo(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(n) != out.Len() || !strings.HasSuffix(out.String(), " 110}") {
+		t.Errorf("unexpected output %d %q", n, out.String())
+	}
+	w := New("a b", "c")
+	out.Reset()
+	n, _ = w.WriteTo(&out)
+	check(out.String(), int(n), w.String(), len(w.String()), t)
+}
+
+func TestReadFrom(t *testing.T) {
+	var s Set[int]
+	n, err := s.ReadFrom(strings.NewReader(" {3 -1 2}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(s.String(), int(n), "{-1 2 3}", 10, t)
+	u := New("x")
+	_, err = u.ReadFrom(strings.NewReader(`{"a b" "c\"d" "e}"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(u.String(), len(u), `{"a b" "c\"d" "e}" "x"}`, 4, t)
+	var out strings.Builder
+	f := New(1.5, -2.25, 3)
+	if _, err = f.WriteTo(&out); err != nil {
+		t.Fatal(err)
+	}
+	g := New[float64]()
+	if _, err = g.ReadFrom(strings.NewReader(out.String())); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Equal(g) {
+		t.Errorf("expected %s, got %s", f, g)
+	}
+	e := New[int]()
+	if _, err = e.ReadFrom(strings.NewReader("{}")); err != nil {
+		t.Fatal(err)
+	}
+	check(e.String(), len(e), "{}", 0, t)
+	for _, text := range []string{"1 2", "{1 x}", `{"1"}`, "{1"} {
+		if _, err = e.ReadFrom(strings.NewReader(text)); err == nil {
+			t.Errorf("expected error for %q", text)
+		}
+	}
+	if _, err = u.ReadFrom(strings.NewReader(`{a}`)); err == nil {
+		t.Error("expected error for unquoted string")
+	}
+	p := New[Pair[int, int]]()
+	if _, err = p.ReadFrom(strings.NewReader("{}")); err != nil {
+		t.Fatal(err) // Empty is fine whatever the type.
+	}
+	if _, err = p.ReadFrom(strings.NewReader("{1}")); err == nil {
+		t.Error("expected error for unsupported element type")
+	}
+}
+
+func TestFlagValue(t *testing.T) {
+	var tags Set[string]
+	var ids Set[int]
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Var(&tags, "tag", "a tag (may be repeated)")
+	flags.Var(&ids, "id", "comma-separated ids")
+	err := flags.Parse([]string{"-tag", "a b", "-tag", "c,d", "-id",
+		"3, 1,2", "-tag", "a b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(tags.String(), len(tags), `{"a b" "c" "d"}`, 3, t)
+	check(ids.String(), len(ids), "{1 2 3}", 3, t)
+	if err = flags.Parse([]string{"-id", "x"}); err == nil {
+		t.Error("expected error for invalid int")
+	}
+}
+
+func TestGoString(t *testing.T) {
+	s := New(3, 1, 2)
+	check(fmt.Sprintf("%#v", s), len(s), "gset.New(1, 2, 3)", 3, t)
+	u := New("b", "a c")
+	check(fmt.Sprintf("%#v", u), len(u), "gset.New(\"a c\", \"b\")", 2, t)
+	e := New[string]()
+	check(fmt.Sprintf("%#v", e), len(e), "gset.New[string]()", 0, t)
+	f := New(2.5, 1.0)
+	check(fmt.Sprintf("%#v", f), len(f), "gset.New[float64](1, 2.5)", 2, t)
+}
+
+func TestFormat(t *testing.T) {
+	s := New(3, 1, 2)
+	check(fmt.Sprintf("%v", s), len(s), "{1 2 3}", 3, t)
+	check(fmt.Sprintf("%s", s), len(s), "{1 2 3}", 3, t)
+	check(fmt.Sprintf("%#v", s), len(s), "gset.New(1, 2, 3)", 3, t)
+	check(fmt.Sprintf("%+v", s), len(s), "{\n\t1\n\t2\n\t3\n}", 3, t)
+	check(fmt.Sprintf("[%9v]", s), len(s), "[  {1 2 3}]", 3, t)
+	check(fmt.Sprintf("[%-9v]", s), len(s), "[{1 2 3}  ]", 3, t)
+	check(fmt.Sprintf("%d", s), len(s), "%!d(gset.Set={1 2 3})", 3, t)
+	check(fmt.Sprintf("%q", s), len(s), `"{1 2 3}"`, 3, t)
+	check(fmt.Sprintf("%x", New(1)), 1, "7b317d", 1, t)
+	check(fmt.Sprintf("% X", New(1)), 1, "7B 31 7D", 1, t)
+	u := New("b", "a")
+	check(fmt.Sprintf("%q", u), len(u), `"{\"a\" \"b\"}"`, 2, t)
+	check(fmt.Sprintf("[%-12q]", New("a")), 1, `["{\"a\"}"   ]`, 1, t)
+	check(fmt.Sprintf("%+v", u), len(u), "{\n\t\"a\"\n\t\"b\"\n}", 2, t)
+	check(fmt.Sprintf("%+v", New[int]()), 0, "{}", 0, t)
+}
+
+func TestStringSorting(t *testing.T) {
+	b := New(true, false)
+	check(b.String(), len(b), "{false true}", 2, t)
+	u := New[uint8](10, 9, 200)
+	check(u.String(), len(u), "{9 10 200}", 3, t)
+	p := New[uintptr](10, 9, 200)
+	check(p.String(), len(p), "{9 10 200}", 3, t)
+	c := New(complex(10, 1), complex(9, 5), complex(9, -1))
+	check(c.String(), len(c), "{(9-1i) (9+5i) (10+1i)}", 3, t)
+	d := New[complex64](complex(10, 1), complex(9, 5), complex(9, -1))
+	check(d.String(), len(d), "{(9-1i) (9+5i) (10+1i)}", 3, t)
+}
+
+func TestCollect(t *testing.T) {
+	s := Collect(slices.Values([]int{3, 1, 2, 3, 1}))
+	check(s.String(), len(s), "{1 2 3}", 3, t)
+	u := Collect(New("a", "b").All())
+	check(u.String(), len(u), `{"a" "b"}`, 2, t)
+	e := Collect(slices.Values([]int(nil)))
+	check(e.String(), len(e), "{}", 0, t)
+	e.Add(1) // Must not be a nil map.
+}
+
+func TestAll(t *testing.T) {
+	s := New(19, 21, 1, 2, 4, 8)
+	var u []int
+	for element := range s.All() {
+		u = append(u, element)
+	}
+	sort.Ints(u)
+	check(fmt.Sprintf("%v", u), len(u), "[1 2 4 8 19 21]", len(s), t)
+	count := 0
+	for range s.All() {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("expected 2 iterations, got %d", count)
+	}
+}
+
 func TestToSlice(t *testing.T) {
 	s := New(19, 21, 1, 2, 4, 8)
 	u := s.ToSlice()
@@ -51,22 +230,255 @@ func TestToSortedSlice(t *testing.T) {
 
 func TestAdd(t *testing.T) {
 	s := New(19, 21, 1, 2, 4, 8)
-	s.Add(5, 7, 1, 19)
+	if n := s.Add(5, 7, 1, 19, 5); n != 2 {
+		t.Errorf("expected 2 added, got %d", n)
+	}
+	check(s.String(), len(s), "{1 2 4 5 7 8 19 21}", 8, t)
+}
+
+func TestToSortedSliceDesc(t *testing.T) {
+	s := New(19, 21, 1, 7, 2, 4, 8, 0)
+	u := s.ToSortedSliceDesc()
+	check(fmt.Sprintf("%v", u), len(u), "[21 19 8 7 4 2 1 0]", len(s), t)
+	w := New("b", "c", "a").ToSortedSliceDesc()
+	check(fmt.Sprintf("%v", w), len(w), "[c b a]", 3, t)
+}
+
+func TestAppendTo(t *testing.T) {
+	s := New(19, 21, 1, 7)
+	dst := make([]int, 0, 10)
+	dst = append(dst, 99, 0)
+	u := s.AppendTo(dst)
+	sort.Ints(u[2:])
+	check(fmt.Sprintf("%v", u), len(u), "[99 0 1 7 19 21]", 6, t)
+	if &u[0] != &dst[0] {
+		t.Error("expected dst's storage to be reused")
+	}
+	u = s.AppendSortedTo([]int{99, 0})
+	check(fmt.Sprintf("%v", u), len(u), "[99 0 1 7 19 21]", 6, t)
+	u = New[int]().AppendSortedTo(nil)
+	check(fmt.Sprintf("%v", u), len(u), "[]", 0, t)
+}
+
+func TestToSortedStrings(t *testing.T) {
+	u := ToSortedStrings(New(10, 9, 100))
+	check(fmt.Sprintf("%v", u), len(u), "[9 10 100]", 3, t)
+	w := ToSortedStrings(New("b", "a c"))
+	check(strings.Join(w, ","), len(w), `"a c","b"`, 2, t)
+	e := ToSortedStrings(New[int]())
+	check(fmt.Sprintf("%v", e), len(e), "[]", 0, t)
+}
+
+func TestForEachSorted(t *testing.T) {
+	s := New(19, 21, 1, 7, 2, 4, 8, 0)
+	var u []int
+	s.ForEachSorted(func(x int) bool {
+		u = append(u, x)
+		return true
+	})
+	check(fmt.Sprintf("%v", u), len(u), "[0 1 2 4 7 8 19 21]", len(s), t)
+	u = nil
+	s.ForEachSorted(func(x int) bool {
+		if x > 4 {
+			return false
+		}
+		u = append(u, x)
+		return true
+	})
+	check(fmt.Sprintf("%v", u), len(u), "[0 1 2 4]", 4, t)
+}
+
+func TestSortedSlice(t *testing.T) {
+	type ID int
+	s := New[ID](10, 9, 100, 1, -5)
+	u := SortedSlice(s)
+	check(fmt.Sprintf("%v", u), len(u), "[-5 1 9 10 100]", len(s), t)
+	w := SortedSlice(New("b", "C", "a"))
+	check(fmt.Sprintf("%v", w), len(w), "[C a b]", 3, t)
+}
+
+func TestAddSlice(t *testing.T) {
+	s := New(19, 21, 1, 2, 4, 8)
+	if n := s.AddSlice([]int{5, 7, 1, 19, 5}); n != 2 {
+		t.Errorf("expected 2 added, got %d", n)
+	}
+	check(s.String(), len(s), "{1 2 4 5 7 8 19 21}", 8, t)
+	if n := s.AddSlice(nil); n != 0 {
+		t.Errorf("expected 0 added, got %d", n)
+	}
+}
+
+func TestAddSeq(t *testing.T) {
+	s := New(19, 21, 1, 2, 4, 8)
+	if n := s.AddSeq(slices.Values([]int{5, 7, 1, 19, 5})); n != 2 {
+		t.Errorf("expected 2 added, got %d", n)
+	}
 	check(s.String(), len(s), "{1 2 4 5 7 8 19 21}", 8, t)
+	if n := s.AddSeq(New(8, 9).All()); n != 1 {
+		t.Errorf("expected 1 added, got %d", n)
+	}
+}
+
+func TestTryAdd(t *testing.T) {
+	s := New(1, 2, 3)
+	if s.TryAdd(2) {
+		t.Error("expected 2 not to be added")
+	}
+	if !s.TryAdd(4) {
+		t.Error("expected 4 to be added")
+	}
+	check(s.String(), len(s), "{1 2 3 4}", 4, t)
+}
+
+func TestToggle(t *testing.T) {
+	s := New(1, 2, 3)
+	if s.Toggle(2) {
+		t.Error("expected 2 to be toggled off")
+	}
+	if !s.Toggle(4) {
+		t.Error("expected 4 to be toggled on")
+	}
+	check(s.String(), len(s), "{1 3 4}", 3, t)
+}
+
+func TestNthSmallest(t *testing.T) {
+	s := New(19, 21, 1, 7, 2, 4, 8, 0, -3)
+	sorted := s.ToSortedSlice()
+	for n, exp := range sorted {
+		if x, ok := NthSmallest(s, n); !ok || x != exp {
+			t.Errorf("n=%d expected %d true, got %d %t", n, exp, x, ok)
+		}
+	}
+	for _, n := range []int{-1, len(s)} {
+		if x, ok := NthSmallest(s, n); ok || x != 0 {
+			t.Errorf("n=%d expected 0 false, got %d %t", n, x, ok)
+		}
+	}
+	if x, ok := NthSmallest(New(2.5, -1.5, 0.5), 1); !ok || x != 0.5 {
+		t.Errorf("expected 0.5 true, got %g %t", x, ok)
+	}
+	r := rand.New(rand.NewSource(1))
+	u := New[int]()
+	for len(u) < 1001 {
+		u.Add(r.Intn(100000))
+	}
+	if x, _ := NthSmallest(u, 500); x != SortedSlice(u)[500] {
+		t.Errorf("expected median %d, got %d", SortedSlice(u)[500], x)
+	}
+}
+
+func TestGrown(t *testing.T) {
+	s := New(3, 1, 2)
+	u := s.Grown(100)
+	check(u.String(), len(u), "{1 2 3}", 3, t)
+	for i := 4; i < 104; i++ {
+		u.Add(i)
+	}
+	if len(u) != 103 {
+		t.Errorf("expected 103 elements, got %d", len(u))
+	}
+	check(s.String(), len(s), "{1 2 3}", 3, t)
+	var z Set[int]
+	z = z.Grown(-5)
+	z.Add(7)
+	check(z.String(), len(z), "{7}", 1, t)
 }
 
 func TestDelete(t *testing.T) {
 	s := New(19, 21, 1, 2, 5, 4, 8, 9, 11, 13, 7)
-	s.Delete(5, 7, 1, 19)
+	if n := s.Delete(5, 7, 1, 19, 23, 5); n != 4 {
+		t.Errorf("expected 4 deleted, got %d", n)
+	}
 	check(s.String(), len(s), "{2 4 8 9 11 13 21}", 7, t)
 }
 
+func TestDiscard(t *testing.T) {
+	s := New(1, 2, 3)
+	if !s.Discard(2) {
+		t.Error("expected 2 to be discarded")
+	}
+	if s.Discard(2) {
+		t.Error("expected 2 to be absent")
+	}
+	check(s.String(), len(s), "{1 3}", 2, t)
+}
+
+func TestRemove(t *testing.T) {
+	s := New(1, 2, 3)
+	if err := s.Remove(2); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := s.Remove(2)
+	if !errors.Is(err, ErrNotPresent) {
+		t.Errorf("expected ErrNotPresent, got %v", err)
+	}
+	check(err.Error(), len(s), "gset: element not present: 2", 2, t)
+	check(s.String(), len(s), "{1 3}", 2, t)
+}
+
+func TestDeleteAll(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	s.DeleteAll(New(2, 4, 6, 8, 10))
+	check(s.String(), len(s), "{0 1 3 5 7 9}", 6, t)
+	u := New(1, 3, 5)
+	u.DeleteAll(New(0, 1, 2, 3, 4, 6, 7, 8, 9, 10))
+	check(u.String(), len(u), "{5}", 1, t)
+	s.DifferenceUpdate(New(0, 9))
+	check(s.String(), len(s), "{1 3 5 7}", 4, t)
+	s.DeleteAll(s)
+	check(s.String(), len(s), "{}", 0, t)
+}
+
+func TestPopFunc(t *testing.T) {
+	s := New(1, 3, 4, 5, 7)
+	x, ok := s.PopFunc(func(x int) bool { return x%2 == 0 })
+	if !ok || x != 4 {
+		t.Errorf("expected 4 true, got %d %t", x, ok)
+	}
+	check(s.String(), len(s), "{1 3 5 7}", 4, t)
+	x, ok = s.PopFunc(func(x int) bool { return x%2 == 0 })
+	if ok || x != 0 {
+		t.Errorf("expected 0 false, got %d %t", x, ok)
+	}
+	check(s.String(), len(s), "{1 3 5 7}", 4, t)
+}
+
+func TestTakeN(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	u := s.TakeN(4)
+	if len(u) != 4 || len(s) != 6 || !s.IsDisjoint(New(u...)) {
+		t.Errorf("unexpected result %v from %s", u, s)
+	}
+	u = append(u, s.TakeN(10)...)
+	check(s.String(), len(s), "{}", 0, t)
+	sort.Ints(u)
+	check(fmt.Sprintf("%v", u), len(u), "[0 1 2 3 4 5 6 7 8 9]", 10, t)
+	s.Add(1, 2)
+	u = s.TakeN(0)
+	check(fmt.Sprintf("%v", u), len(s), "[]", 2, t)
+}
+
 func TestClear(t *testing.T) {
 	s := New(19, 21, 1, 2, 5, 4, 8, 9, 11, 13, 7)
 	s.Clear()
 	check(s.String(), len(s), "{}", 0, t)
 }
 
+func TestSingle(t *testing.T) {
+	s := New(7)
+	if x, ok := s.Single(); !s.IsSingleton() || !ok || x != 7 {
+		t.Errorf("expected 7 true, got %d %t", x, ok)
+	}
+	s.Add(8)
+	if x, ok := s.Single(); s.IsSingleton() || ok || x != 0 {
+		t.Errorf("expected 0 false, got %d %t", x, ok)
+	}
+	var z Set[string]
+	if x, ok := z.Single(); z.IsSingleton() || ok || x != "" {
+		t.Errorf("expected \"\" false, got %q %t", x, ok)
+	}
+}
+
 func TestContains(t *testing.T) {
 	s := New(19, 21, 1, 2, 5, 4, 8, 9, 11, 13, 7)
 	if !s.Contains(11) {
@@ -77,6 +489,189 @@ func TestContains(t *testing.T) {
 	}
 }
 
+func TestContainsAll(t *testing.T) {
+	s := New(19, 21, 1, 2, 5, 4, 8, 9, 11, 13, 7)
+	if !s.ContainsAll(1, 2, 21) {
+		t.Error("expected set to contain 1, 2, and 21")
+	}
+	if s.ContainsAll(1, 23, 2) {
+		t.Error("expected set not to contain all of 1, 23, and 2")
+	}
+	if !s.ContainsAll() {
+		t.Error("expected true for no elements")
+	}
+}
+
+func TestContainsAny(t *testing.T) {
+	s := New(19, 21, 1, 2, 5, 4, 8, 9, 11, 13, 7)
+	if !s.ContainsAny(23, 2, 50) {
+		t.Error("expected set to contain 2")
+	}
+	if s.ContainsAny(23, 50, 0) {
+		t.Error("expected set not to contain any of 23, 50, and 0")
+	}
+	if s.ContainsAny() {
+		t.Error("expected false for no elements")
+	}
+}
+
+func TestFindFunc(t *testing.T) {
+	s := New(1, 3, 4, 5, 7)
+	if x, ok := s.FindFunc(func(x int) bool { return x%2 == 0 }); !ok ||
+		x != 4 {
+		t.Errorf("expected 4 true, got %d %t", x, ok)
+	}
+	if x, ok := s.FindFunc(func(x int) bool { return x > 3 }); !ok || x < 4 {
+		t.Errorf("expected an element > 3, got %d %t", x, ok)
+	}
+	if x, ok := s.FindFunc(func(x int) bool { return x > 9 }); ok || x != 0 {
+		t.Errorf("expected 0 false, got %d %t", x, ok)
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	s := New(2, 4, 5, 6)
+	calls := 0
+	even := func(x int) bool { calls++; return x%2 == 0 }
+	all := func(acc bool, x int) bool { return acc && even(x) }
+	some := func(acc bool, x int) bool { return acc || even(x) }
+	if s.Satisfies(true, all, false) {
+		t.Error("expected not all even")
+	}
+	if !New(2, 4).Satisfies(true, all, false) {
+		t.Error("expected all even")
+	}
+	if !s.Satisfies(false, some, true) {
+		t.Error("expected some even")
+	}
+	if New(1, 3).Satisfies(false, some, true) {
+		t.Error("expected none even")
+	}
+	calls = 0
+	New(1, 3, 5, 7, 9).Satisfies(true, all, false)
+	if calls != 1 {
+		t.Errorf("expected 1 call before stopping, got %d", calls)
+	}
+	var e Set[int]
+	if !e.Satisfies(true, all, false) || e.Satisfies(false, some, true) {
+		t.Error("expected initial for an empty set")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	s := New(1, 2, 3, 4, 5, 6)
+	even := func(x int) bool { return x%2 == 0 }
+	u := s.Filter(even)
+	check(u.String(), len(u), "{2 4 6}", 3, t)
+	dst := New(99)
+	s.FilterInto(func(x int) bool { return x > 4 }, dst)
+	check(dst.String(), len(dst), "{5 6}", 2, t)
+	New[int]().FilterInto(even, dst)
+	check(dst.String(), len(dst), "{}", 0, t)
+	check(s.String(), len(s), "{1 2 3 4 5 6}", 6, t)
+}
+
+func TestMapInPlace(t *testing.T) {
+	s := New("Alpha", "ALPHA", "beta", "Gamma")
+	s.MapInPlace(strings.ToLower)
+	check(s.String(), len(s), `{"alpha" "beta" "gamma"}`, 3, t)
+	u := New(1, 2, 3)
+	u.MapInPlace(func(x int) int { return x + 1 })
+	check(u.String(), len(u), "{2 3 4}", 3, t)
+	var e Set[int]
+	e.MapInPlace(func(x int) int { return x })
+	check(e.String(), len(e), "{}", 0, t)
+}
+
+func TestRandomElement(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := New(0, 1, 2, 3)
+	counts := make([]int, len(s))
+	for i := 0; i < 4000; i++ {
+		x, ok := s.RandomElement(r)
+		if !ok || !s.Contains(x) {
+			t.Fatalf("unexpected element %d %t", x, ok)
+		}
+		counts[x]++
+	}
+	for x, count := range counts {
+		if count < 800 || count > 1200 {
+			t.Errorf("element %d chosen %d times", x, count)
+		}
+	}
+	a, _ := s.RandomElement(rand.New(rand.NewSource(7)))
+	b, _ := s.RandomElement(rand.New(rand.NewSource(7)))
+	if a != b {
+		t.Errorf("expected the same element for the same seed: %d %d", a, b)
+	}
+	if x, ok := New[int]().RandomElement(r); ok || x != 0 {
+		t.Error("expected zero and false for empty set")
+	}
+	u := New[Pair[string, int]]()
+	for i := 0; i < 50; i++ {
+		u.Add(Pair[string, int]{strconv.Itoa(i * 7 % 50), i})
+	}
+	sorted := u.ToSortedSlice()
+	for seed := int64(0); seed < 20; seed++ {
+		x, _ := u.RandomElement(rand.New(rand.NewSource(seed)))
+		k := rand.New(rand.NewSource(seed)).Intn(len(u))
+		if x != sorted[k] {
+			t.Errorf("seed %d: expected %v, got %v", seed, sorted[k], x)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	counts := make([]int, len(s))
+	for i := 0; i < 5000; i++ {
+		sample := s.Sample(3, r)
+		if len(sample) != 3 || len(New(sample...)) != 3 {
+			t.Fatalf("expected 3 distinct elements, got %v", sample)
+		}
+		for _, x := range sample {
+			counts[x]++
+		}
+	}
+	for x, count := range counts {
+		if count < 1300 || count > 1700 {
+			t.Errorf("element %d chosen %d times", x, count)
+		}
+	}
+	all := s.Sample(20, r)
+	if !New(all...).Equal(s) || len(all) != len(s) {
+		t.Errorf("expected all elements, got %v", all)
+	}
+	if none := s.Sample(0, r); len(none) != 0 {
+		t.Errorf("expected no elements, got %v", none)
+	}
+}
+
+func TestSampleSeed(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	a := s.SampleSeed(4, 42)
+	b := s.Sample(4, rand.New(rand.NewSource(42)))
+	check(fmt.Sprintf("%v", a), len(a), fmt.Sprintf("%v", b), 4, t)
+	c := s.SampleSeed(4, 42)
+	check(fmt.Sprintf("%v", a), len(a), fmt.Sprintf("%v", c), len(c), t)
+}
+
+func TestShuffledSlice(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	a := s.ShuffledSlice(rand.New(rand.NewSource(1)))
+	if len(a) != len(s) || !New(a...).Equal(s) {
+		t.Errorf("expected all elements, got %v", a)
+	}
+	if sort.IntsAreSorted(a) {
+		t.Errorf("expected shuffled elements, got %v", a)
+	}
+	b := s.ShuffledSlice(rand.New(rand.NewSource(1)))
+	check(fmt.Sprintf("%v", a), len(a), fmt.Sprintf("%v", b), len(b), t)
+	e := New[int]().ShuffledSlice(rand.New(rand.NewSource(1)))
+	check(fmt.Sprintf("%v", e), len(e), "[]", 0, t)
+}
+
 func TestDifference(t *testing.T) {
 	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
 	u := New(2, 4, 6, 8)
@@ -86,6 +681,71 @@ func TestDifference(t *testing.T) {
 	check(d.String(), len(d), "{}", 0, t)
 }
 
+func TestComplement(t *testing.T) {
+	universe := New(0, 1, 2, 3, 4, 5)
+	c := New(1, 3, 5, 7).Complement(universe)
+	check(c.String(), len(c), "{0 2 4}", 3, t)
+	c = New[int]().Complement(universe)
+	check(c.String(), len(c), "{0 1 2 3 4 5}", 6, t)
+}
+
+func TestDifferenceInto(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	u := New(2, 4, 6, 8)
+	dst := New(99)
+	s.DifferenceInto(u, dst)
+	check(dst.String(), len(dst), "{0 1 3 5 7 9}", 6, t)
+	u.DifferenceInto(s, dst)
+	check(dst.String(), len(dst), "{}", 0, t)
+}
+
+func BenchmarkDifference(b *testing.B) {
+	small, large := lopsidedSets()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		large.Difference(small)
+	}
+}
+
+func BenchmarkDifferenceInto(b *testing.B) {
+	small, large := lopsidedSets()
+	dst := New[int]()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		large.DifferenceInto(small, dst)
+	}
+}
+
+func TestDifferenceCardinality(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	u := New(2, 4, 6, 8, 10)
+	if n := s.DifferenceCardinality(u); n != 6 {
+		t.Errorf("expected 6, got %d", n)
+	}
+	if n := u.DifferenceCardinality(s); n != 1 {
+		t.Errorf("expected 1, got %d", n)
+	}
+	if n := New[int]().DifferenceCardinality(s); n != 0 {
+		t.Errorf("expected 0, got %d", n)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := New(1, 2, 3, 4)
+	after := New(3, 4, 5)
+	added, removed := before.Diff(after)
+	check(added.String(), len(added), "{5}", 1, t)
+	check(removed.String(), len(removed), "{1 2}", 2, t)
+	added, removed = before.Diff(before)
+	if added == nil || removed == nil {
+		t.Error("expected non-nil sets")
+	}
+	check(added.String()+removed.String(), len(added)+len(removed), "{}{}",
+		0, t)
+}
+
 func TestSymmetricDifference(t *testing.T) {
 	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
 	u := New(2, 4, 6, 8)
@@ -95,11 +755,114 @@ func TestSymmetricDifference(t *testing.T) {
 	check(d.String(), len(d), "{0 1 3 5 7 9}", 6, t)
 }
 
+func TestSymmetricDifferenceInto(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	u := New(2, 4, 6, 8, 10)
+	dst := New(99)
+	s.SymmetricDifferenceInto(u, dst)
+	check(dst.String(), len(dst), "{0 1 3 5 7 9 10}", 7, t)
+	u.SymmetricDifferenceInto(s, dst)
+	check(dst.String(), len(dst), "{0 1 3 5 7 9 10}", 7, t)
+	s.SymmetricDifferenceInto(s.Copy(), dst)
+	check(dst.String(), len(dst), "{}", 0, t)
+}
+
+func TestSymmetricDifferenceCardinality(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	u := New(2, 4, 6, 8, 10, 12)
+	if n := s.SymmetricDifferenceCardinality(u); n != 8 {
+		t.Errorf("expected 8, got %d", n)
+	}
+	if n := u.SymmetricDifferenceCardinality(s); n != 8 {
+		t.Errorf("expected 8, got %d", n)
+	}
+	if n := s.SymmetricDifferenceCardinality(s); n != 0 {
+		t.Errorf("expected 0, got %d", n)
+	}
+}
+
 func TestIntersection(t *testing.T) {
 	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
 	u := New(2, 4, 6, 8)
 	x := s.Intersection(u)
 	check(x.String(), len(x), "{2 4 6 8}", 4, t)
+	x = u.Intersection(s)
+	check(x.String(), len(x), "{2 4 6 8}", 4, t)
+	small, large := lopsidedSets()
+	x = large.Intersection(small)
+	if !x.Equal(intersectionBothWays(large, small)) {
+		t.Errorf("expected %s, got %s", small, x)
+	}
+}
+
+func TestIntersectionInto(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	dst := New(99)
+	s.IntersectionInto(New(2, 4, 6, 8, 10), dst)
+	check(dst.String(), len(dst), "{2 4 6 8}", 4, t)
+	New(2, 4, 6, 8, 10).IntersectionInto(s, dst)
+	check(dst.String(), len(dst), "{2 4 6 8}", 4, t)
+	s.IntersectionInto(nil, dst)
+	check(dst.String(), len(dst), "{}", 0, t)
+}
+
+func TestIntersectionCardinality(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	u := New(2, 4, 6, 8, 10)
+	if n := s.IntersectionCardinality(u); n != 4 {
+		t.Errorf("expected 4, got %d", n)
+	}
+	if n := u.IntersectionCardinality(s); n != 4 {
+		t.Errorf("expected 4, got %d", n)
+	}
+	if n := s.IntersectionCardinality(New[int]()); n != 0 {
+		t.Errorf("expected 0, got %d", n)
+	}
+}
+
+// intersectionBothWays is the original Intersection algorithm, kept for
+// benchmarking.
+func intersectionBothWays[T comparable](a, b Set[T]) Set[T] {
+	intersection := Set[T]{}
+	for element := range a {
+		if b.Contains(element) {
+			intersection[element] = struct{}{}
+		}
+	}
+	for element := range b {
+		if a.Contains(element) {
+			intersection[element] = struct{}{}
+		}
+	}
+	return intersection
+}
+
+func lopsidedSets() (Set[int], Set[int]) {
+	small := New[int]()
+	for i := 0; i < 10; i++ {
+		small.Add(i * 1000)
+	}
+	large := New[int]()
+	for i := 0; i < 100000; i++ {
+		large.Add(i)
+	}
+	return small, large
+}
+
+func BenchmarkIntersectionBothWays(b *testing.B) {
+	small, large := lopsidedSets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		intersectionBothWays(small, large)
+	}
+}
+
+func BenchmarkIntersection(b *testing.B) {
+	small, large := lopsidedSets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		small.Intersection(large)
+	}
 }
 
 func TestUnion(t *testing.T) {
@@ -107,12 +870,55 @@ func TestUnion(t *testing.T) {
 	u := New(2, 4, 6, 8, 10, 12)
 	x := s.Union(u)
 	check(x.String(), len(x), "{0 1 2 3 4 5 6 7 8 9 10 12}", 12, t)
+	x = u.Union(s)
+	check(x.String(), len(x), "{0 1 2 3 4 5 6 7 8 9 10 12}", 12, t)
+}
+
+func BenchmarkUnionSmallWithLarge(b *testing.B) {
+	small, large := lopsidedSets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		small.Union(large)
+	}
+}
+
+func TestUnionInto(t *testing.T) {
+	s := New(0, 1, 2, 3)
+	dst := New(99, 100)
+	s.UnionInto(New(2, 4), dst)
+	check(dst.String(), len(dst), "{0 1 2 3 4}", 5, t)
+	New[int]().UnionInto(nil, dst)
+	check(dst.String(), len(dst), "{}", 0, t)
+	check(s.String(), len(s), "{0 1 2 3}", 4, t)
+}
+
+func TestUnionCardinality(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	u := New(2, 4, 6, 8, 10)
+	if n := s.UnionCardinality(u); n != 11 {
+		t.Errorf("expected 11, got %d", n)
+	}
+	if n := u.UnionCardinality(s); n != 11 {
+		t.Errorf("expected 11, got %d", n)
+	}
+	if n := s.UnionCardinality(nil); n != 10 {
+		t.Errorf("expected 10, got %d", n)
+	}
 }
 
 func TestUnite(t *testing.T) {
 	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
-	s.Unite(New(2, 4, 6, 8, 10, 12))
+	if n := s.Unite(New(2, 4, 6, 8, 10, 12)); n != 2 {
+		t.Errorf("expected 2 added, got %d", n)
+	}
 	check(s.String(), len(s), "{0 1 2 3 4 5 6 7 8 9 10 12}", 12, t)
+	if n := s.Unite(New(13), New[int](), New(12, 14, 13)); n != 2 {
+		t.Errorf("expected 2 added, got %d", n)
+	}
+	check(s.String(), len(s), "{0 1 2 3 4 5 6 7 8 9 10 12 13 14}", 14, t)
+	if n := s.Unite(); n != 0 {
+		t.Errorf("expected 0 added, got %d", n)
+	}
 }
 
 func TestCopy(t *testing.T) {
@@ -133,33 +939,269 @@ func TestEqual(t *testing.T) {
 	}
 }
 
-func TestIsDisjoing(t *testing.T) {
-	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
-	u := s.Copy()
-	if s.IsDisjoint(u) {
-		t.Error("unexpectedly disjoint")
+func TestEqualSlice(t *testing.T) {
+	s := New(1, 2, 3)
+	if !s.EqualSlice([]int{3, 1, 2, 1}) {
+		t.Error("expected set to equal slice")
 	}
-	w := New(10, 11, 12)
-	if !u.IsDisjoint(w) {
-		t.Error("unexpectedly not disjoint")
+	if s.EqualSlice([]int{3, 1, 1}) || s.EqualSlice([]int{3, 1, 2, 4}) {
+		t.Error("expected set not to equal slice")
+	}
+	if !New[int]().EqualSlice(nil) {
+		t.Error("expected empty set to equal nil slice")
 	}
 }
 
-func TestMap(t *testing.T) {
-	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
-	if !s.Contains(7) {
-		t.Error("expected to contain 7")
+func TestEqualFunc(t *testing.T) {
+	type item struct {
+		id   int
+		note string
 	}
-	if _, ok := s[7]; !ok {
-		t.Error("expected to contain 7")
+	sameID := func(x, y item) bool { return x.id == y.id }
+	a := New(item{1, "a"}, item{2, "b"})
+	b := New(item{2, "x"}, item{1, "y"})
+	if a.Equal(b) || !EqualFunc(a, b, sameID) {
+		t.Error("expected equal by id only")
 	}
-	v := s.ToSortedSlice()
-	check(fmt.Sprintf("%v", v), len(v), "[0 1 2 3 4 5 6 7 8 9]", len(s), t)
-	w := make([]int, 0, len(s))
-	for x := range v {
-		w = append(w, x)
+	b.Add(item{3, "z"})
+	if EqualFunc(a, b, sameID) {
+		t.Error("expected different sizes to be unequal")
 	}
-	sort.Ints(w)
+	near := func(x, y int) bool { return x-y <= 1 && y-x <= 1 }
+	// 1 could pair with 1 or 2 but must pair with 1 to leave 2 for 3.
+	if !EqualFunc(New(1, 3), New(1, 2), near) {
+		t.Error("expected a pairing to be found")
+	}
+	if EqualFunc(New(1, 2), New(1, 5), near) {
+		t.Error("expected no pairing for 5")
+	}
+	if EqualFunc(New(1, 2), New(1, 7), near) {
+		t.Error("expected no pairing for 7")
+	}
+	if !EqualFunc(New[int](), nil, near) {
+		t.Error("expected empty sets to be equal")
+	}
+}
+
+func TestRelationTo(t *testing.T) {
+	s := New(1, 2, 3)
+	for _, x := range []struct {
+		other Set[int]
+		exp   Relation
+	}{
+		{New(3, 2, 1), Equal},
+		{New(1, 2, 3, 4), Subset},
+		{New(1, 3), Superset},
+		{New(4, 5), Disjoint},
+		{New(3, 4), Overlapping},
+		{New[int](), Superset},
+	} {
+		if act := s.RelationTo(x.other); act != x.exp {
+			t.Errorf("%s vs %s: expected %s, got %s", s, x.other, x.exp, act)
+		}
+	}
+	var z Set[int]
+	if act := z.RelationTo(nil); act != Equal {
+		t.Errorf("expected Equal, got %s", act)
+	}
+	if act := z.RelationTo(s); act != Subset {
+		t.Errorf("expected Subset, got %s", act)
+	}
+	check(Relation(9).String(), 0, "Relation(9)", 0, t)
+}
+
+func TestJaccard(t *testing.T) {
+	a := New(1, 2, 3, 4)
+	b := New(3, 4, 5)
+	if act := a.Jaccard(b); math.Abs(act-2.0/5) > 1e-9 {
+		t.Errorf("expected 0.4, got %g", act)
+	}
+	if act := a.JaccardDistance(b); math.Abs(act-3.0/5) > 1e-9 {
+		t.Errorf("expected 0.6, got %g", act)
+	}
+	if act := a.JaccardDistance(a); act != 0 {
+		t.Errorf("expected 0, got %g", act)
+	}
+	if act := a.JaccardDistance(New(7)); act != 1 {
+		t.Errorf("expected 1, got %g", act)
+	}
+	e := New[int]()
+	if e.Jaccard(nil) != 1 || e.JaccardDistance(nil) != 0 {
+		t.Errorf("expected 1 and 0 for empty sets, got %g and %g",
+			e.Jaccard(nil), e.JaccardDistance(nil))
+	}
+}
+
+func TestTversky(t *testing.T) {
+	a := New(1, 2, 3, 4)
+	b := New(3, 4, 5)
+	for _, x := range []struct {
+		alpha, beta, exp float64
+	}{
+		{1, 1, 2.0 / 5},     // Jaccard
+		{0.5, 0.5, 4.0 / 7}, // Dice
+		{1, 0, 2.0 / 4},
+		{0, 1, 2.0 / 3},
+	} {
+		if act := a.Tversky(b, x.alpha, x.beta); math.Abs(act-x.exp) > 1e-9 {
+			t.Errorf("α=%g β=%g: expected %g, got %g", x.alpha, x.beta,
+				x.exp, act)
+		}
+	}
+	e := New[int]()
+	if act := e.Tversky(nil, 1, 1); act != 1 {
+		t.Errorf("expected 1 for empty sets, got %g", act)
+	}
+	if act := e.Tversky(b, 0, 0); act != 0 {
+		t.Errorf("expected 0 for zero denominator, got %g", act)
+	}
+}
+
+func TestHash(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	u := New(9, 8, 7, 6, 5, 4, 3, 2, 1, 0)
+	if s.Hash() != u.Hash() {
+		t.Errorf("expected equal hashes for %s and %s", s, u)
+	}
+	u.Delete(5)
+	if s.Hash() == u.Hash() {
+		t.Errorf("expected different hashes for %s and %s", s, u)
+	}
+	if New[string]().Hash() != New[string]().Hash() {
+		t.Error("expected equal hashes for empty sets")
+	}
+	if New("ab", "c").Hash() == New("a", "bc").Hash() {
+		t.Error("expected different hashes")
+	}
+	negZero := math.Copysign(0, -1)
+	if New(0.0, 1.5).Hash() != New(negZero, 1.5).Hash() {
+		t.Error("expected 0 and -0 to hash the same")
+	}
+	c, nc := New(complex(0, 0)), New(complex(negZero, negZero))
+	if !c.Equal(nc) || c.Hash() != nc.Hash() {
+		t.Errorf("expected equal hashes for %v and %v", c, nc)
+	}
+	p := New(Pair[float64, int]{0, 1}, Pair[float64, int]{2, 3})
+	np := New(Pair[float64, int]{2, 3}, Pair[float64, int]{negZero, 1})
+	if !p.Equal(np) || p.Hash() != np.Hash() {
+		t.Errorf("expected equal hashes for %v and %v", p, np)
+	}
+	if p.Hash() == New(Pair[float64, int]{0, 2}, Pair[float64, int]{2,
+		3}).Hash() {
+		t.Error("expected different hashes for different pairs")
+	}
+	buckets := map[uint64][]Set[int]{}
+	for _, x := range []Set[int]{New(1, 2), New(3), New(2, 1)} {
+		buckets[x.Hash()] = append(buckets[x.Hash()], x)
+	}
+	if len(buckets) != 2 {
+		t.Errorf("expected 2 buckets, got %d", len(buckets))
+	}
+}
+
+func TestIsDisjoing(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	u := s.Copy()
+	if s.IsDisjoint(u) {
+		t.Error("unexpectedly disjoint")
+	}
+	w := New(10, 11, 12)
+	if !u.IsDisjoint(w) || !w.IsDisjoint(u) {
+		t.Error("unexpectedly not disjoint")
+	}
+	w.Add(9, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22)
+	if u.IsDisjoint(w) || w.IsDisjoint(u) {
+		t.Error("unexpectedly disjoint")
+	}
+}
+
+func TestSubsetOfAny(t *testing.T) {
+	roles := []Set[string]{New("read"), New("read", "write"),
+		New("admin", "read")}
+	if !New("read", "write").SubsetOfAny(roles...) {
+		t.Error("expected {read write} to be a subset of a role")
+	}
+	if New("admin", "write").SubsetOfAny(roles...) {
+		t.Error("expected {admin write} not to be a subset of any role")
+	}
+	if !New[string]().SubsetOfAny(nil) || New[string]().SubsetOfAny() {
+		t.Error("unexpected result for empty sets")
+	}
+}
+
+func TestSupersetOfAll(t *testing.T) {
+	capabilities := New("read", "write", "delete")
+	if !capabilities.SupersetOfAll(New("read"), New("write", "delete"),
+		nil) {
+		t.Error("expected capabilities to cover all the sets")
+	}
+	if capabilities.SupersetOfAll(New("read"), New("read", "admin")) {
+		t.Error("expected capabilities not to cover {admin read}")
+	}
+	if !New[string]().SupersetOfAll() {
+		t.Error("expected an empty set to be a superset of no sets")
+	}
+}
+
+func TestIntersects(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	if !s.Intersects(New(9, 10)) || !New(9, 10).Intersects(s) {
+		t.Error("expected sets to intersect")
+	}
+	if s.Intersects(New(10, 11)) || s.Intersects(nil) {
+		t.Error("expected sets not to intersect")
+	}
+}
+
+// isDisjointBothWays is the original IsDisjoint algorithm, kept for
+// benchmarking.
+func isDisjointBothWays[T comparable](a, b Set[T]) bool {
+	for element := range a {
+		if b.Contains(element) {
+			return false
+		}
+	}
+	for element := range b {
+		if a.Contains(element) {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkIsDisjointBothWays(b *testing.B) {
+	small, large := lopsidedSets()
+	small = Map(small, func(x int) int { return -x - 1 })
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		isDisjointBothWays(large, small)
+	}
+}
+
+func BenchmarkIsDisjoint(b *testing.B) {
+	small, large := lopsidedSets()
+	small = Map(small, func(x int) int { return -x - 1 })
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		large.IsDisjoint(small)
+	}
+}
+
+func TestMap(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	if !s.Contains(7) {
+		t.Error("expected to contain 7")
+	}
+	if _, ok := s[7]; !ok {
+		t.Error("expected to contain 7")
+	}
+	v := s.ToSortedSlice()
+	check(fmt.Sprintf("%v", v), len(v), "[0 1 2 3 4 5 6 7 8 9]", len(s), t)
+	w := make([]int, 0, len(s))
+	for x := range v {
+		w = append(w, x)
+	}
+	sort.Ints(w)
 	check(fmt.Sprintf("%v", w), len(w), "[0 1 2 3 4 5 6 7 8 9]", len(s), t)
 	if len(w) != 10 {
 		t.Error("expected 10 elements")
@@ -180,3 +1222,1045 @@ func TestStringMaxElements(t *testing.T) {
 	}
 	check(s.String(), len(s), "{…111 elements…}", len(s), t)
 }
+
+func TestPowerSet(t *testing.T) {
+	s := New(1, 2, 3)
+	p := s.PowerSet()
+	if len(p) != 8 {
+		t.Errorf("expected 8 subsets, got %d", len(p))
+	}
+	seen := New[string]()
+	for _, subset := range p {
+		seen.Add(subset.String())
+	}
+	check(seen.String(), len(seen),
+		"{\"{1 2 3}\" \"{1 2}\" \"{1 3}\" \"{1}\" \"{2 3}\" \"{2}\" "+
+			"\"{3}\" \"{}\"}", 8, t)
+	p[len(p)-1].Add(99)
+	if s.Contains(99) {
+		t.Error("expected subsets to be independent of the original")
+	}
+	e := New[int]().PowerSet()
+	if len(e) != 1 || !e[0].IsEmpty() {
+		t.Errorf("expected one empty subset, got %v", e)
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	s := New(4, 1, 3, 2)
+	c := s.Combinations(2)
+	check(fmt.Sprintf("%v", c), len(c), "[[1 2] [1 3] [1 4] [2 3] [2 4] [3 4]]",
+		6, t)
+	c = s.Combinations(4)
+	check(fmt.Sprintf("%v", c), len(c), "[[1 2 3 4]]", 1, t)
+	c = s.Combinations(0)
+	check(fmt.Sprintf("%v", c), len(c), "[[]]", 1, t)
+	c = s.Combinations(5)
+	check(fmt.Sprintf("%v", c), len(c), "[]", 0, t)
+	c = s.Combinations(-1)
+	check(fmt.Sprintf("%v", c), len(c), "[]", 0, t)
+	c = New[int]().Combinations(0)
+	check(fmt.Sprintf("%v", c), len(c), "[[]]", 1, t)
+}
+
+func TestCombinationsSeq(t *testing.T) {
+	s := New(4, 1, 3, 2)
+	var c [][]int
+	for combination := range s.CombinationsSeq(3) {
+		c = append(c, combination)
+	}
+	check(fmt.Sprintf("%v", c), len(c), "[[1 2 3] [1 2 4] [1 3 4] [2 3 4]]",
+		4, t)
+	c = nil
+	for combination := range s.CombinationsSeq(2) {
+		c = append(c, combination)
+		if len(c) == 2 {
+			break
+		}
+	}
+	check(fmt.Sprintf("%v", c), len(c), "[[1 2] [1 3]]", 2, t)
+	for range s.CombinationsSeq(5) {
+		t.Error("expected no combinations")
+	}
+}
+
+func TestPermutations(t *testing.T) {
+	s := New("c", "a", "b")
+	var p [][]string
+	for permutation := range s.Permutations() {
+		p = append(p, permutation)
+	}
+	check(fmt.Sprintf("%v", p), len(p),
+		"[[a b c] [a c b] [b a c] [b c a] [c a b] [c b a]]", 6, t)
+	count := 0
+	for range New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10).Permutations() {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	if count != 10 {
+		t.Errorf("expected 10 iterations, got %d", count)
+	}
+	p = nil
+	for permutation := range New[string]().Permutations() {
+		p = append(p, permutation)
+	}
+	check(fmt.Sprintf("%v", p), len(p), "[[]]", 1, t)
+}
+
+func TestChunk(t *testing.T) {
+	s := New(5, 4, 3, 2, 1, 7, 6)
+	c := s.Chunk(3)
+	check(fmt.Sprintf("%v", c), len(c), "[[1 2 3] [4 5 6] [7]]", 3, t)
+	c[0] = append(c[0], 99)
+	check(fmt.Sprintf("%v", c), len(c), "[[1 2 3 99] [4 5 6] [7]]", 3, t)
+	c = s.Chunk(7)
+	check(fmt.Sprintf("%v", c), len(c), "[[1 2 3 4 5 6 7]]", 1, t)
+	c = New[int]().Chunk(2)
+	check(fmt.Sprintf("%v", c), len(c), "[]", 0, t)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for zero size")
+		}
+	}()
+	s.Chunk(0)
+}
+
+func TestPartitionN(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	p := s.PartitionN(3)
+	check(fmt.Sprintf("%v", p), len(p), "[{0 3 6 9} {1 4 7} {2 5 8}]", 3, t)
+	p = New(1).PartitionN(2)
+	check(fmt.Sprintf("%v", p), len(p), "[{1} {}]", 2, t)
+	p[1].Add(2) // Must not be a nil map.
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for zero k")
+		}
+	}()
+	s.PartitionN(0)
+}
+
+func TestCartesianProduct(t *testing.T) {
+	a := New(1, 2)
+	b := New("x", "y", "z")
+	p := CartesianProduct(a, b)
+	check(p.String(), len(p), "{{1 x} {1 y} {1 z} {2 x} {2 y} {2 z}}", 6, t)
+	if !p.Contains(Pair[int, string]{2, "y"}) {
+		t.Error("expected product to contain {2 y}")
+	}
+	e := CartesianProduct(a, New[string]())
+	check(e.String(), len(e), "{}", 0, t)
+}
+
+func TestSafeSet(t *testing.T) {
+	s := NewSafe[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Add(i*100 + j)
+				_ = s.Contains(j)
+				_ = s.Len()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if s.Len() != 1000 {
+		t.Errorf("expected 1000 elements, got %d", s.Len())
+	}
+	s.Clear()
+	s.Add(1, 2, 3)
+	u := s.Union(New(3, 4))
+	check(u.String(), len(u), "{1 2 3 4}", 4, t)
+	u.Add(5)
+	check(s.String(), s.Len(), "{1 2 3}", 3, t)
+	if n := s.Delete(2, 5); n != 1 {
+		t.Errorf("expected 1 deleted, got %d", n)
+	}
+	check(s.String(), s.Len(), "{1 3}", 2, t)
+}
+
+func TestSafeSetZeroValue(t *testing.T) {
+	var s SafeSet[int]
+	check(s.String(), s.Len(), "{}", 0, t)
+	if s.Contains(1) || s.Delete(1) != 0 || !s.IsEmpty() {
+		t.Error("expected an empty set")
+	}
+	if n := s.Add(2, 1, 2); n != 2 {
+		t.Errorf("expected 2 added, got %d", n)
+	}
+	check(s.String(), s.Len(), "{1 2}", 2, t)
+	var u SafeSet[int]
+	if n := u.Unite(New(3), New(4)); n != 2 {
+		t.Errorf("expected 2 added, got %d", n)
+	}
+	check(u.String(), u.Len(), "{3 4}", 2, t)
+}
+
+func TestSafeSetSnapshot(t *testing.T) {
+	s := NewSafe(1, 2, 3)
+	u := s.Snapshot()
+	for element := range u {
+		s.Add(element * 10) // Safe because u is independent.
+	}
+	check(u.String(), len(u), "{1 2 3}", 3, t)
+	check(s.String(), s.Len(), "{1 2 3 10 20 30}", 6, t)
+}
+
+func TestSafeSetReplace(t *testing.T) {
+	s := NewSafe(1, 2, 3)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if n := s.Len(); n != 3 && n != 4 {
+				t.Errorf("unexpected length %d", n)
+			}
+		}
+	}()
+	u := New(4, 5, 6, 7)
+	s.Replace(u)
+	wg.Wait()
+	u.Add(8) // s has a copy so mustn't see this.
+	check(s.String(), s.Len(), "{4 5 6 7}", 4, t)
+}
+
+func TestOrderedSet(t *testing.T) {
+	s := NewOrderedSet(5, 3, 9, 1)
+	check(s.String(), s.Len(), "{5 3 9 1}", 4, t)
+	if n := s.Add(3, 7, 5, 7); n != 1 {
+		t.Errorf("expected 1 added, got %d", n)
+	}
+	check(s.String(), s.Len(), "{5 3 9 1 7}", 5, t)
+	if n := s.Delete(9, 5, 11, 9); n != 2 {
+		t.Errorf("expected 2 deleted, got %d", n)
+	}
+	check(s.String(), s.Len(), "{3 1 7}", 3, t)
+	if !s.Contains(7) || s.Contains(9) {
+		t.Error("unexpected membership")
+	}
+	s.Add(9)
+	check(fmt.Sprintf("%v", s.ToSlice()), s.Len(), "[3 1 7 9]", 4, t)
+	s.Delete(3, 1, 7)
+	s.Add(2)
+	check(s.String(), s.Len(), "{9 2}", 2, t)
+	s.Clear()
+	check(s.String(), s.Len(), "{}", 0, t)
+	var z OrderedSet[string]
+	if n := z.Delete("a"); n != 0 {
+		t.Errorf("expected 0 deleted, got %d", n)
+	}
+	if n := z.Add("b", "a"); n != 2 {
+		t.Errorf("expected 2 added, got %d", n)
+	}
+	check(z.String(), z.Len(), "{\"b\" \"a\"}", 2, t)
+}
+
+func TestCartesianProductN(t *testing.T) {
+	var p [][]int
+	for tuple := range CartesianProductN(New(2, 1), New(3), New(5, 4)) {
+		p = append(p, tuple)
+	}
+	check(fmt.Sprintf("%v", p), len(p), "[[1 3 4] [1 3 5] [2 3 4] [2 3 5]]",
+		4, t)
+	for range CartesianProductN(New(1), New[int]()) {
+		t.Error("expected no tuples")
+	}
+	p = nil
+	for tuple := range CartesianProductN[int]() {
+		p = append(p, tuple)
+	}
+	check(fmt.Sprintf("%v", p), len(p), "[[]]", 1, t)
+	p = nil
+	for tuple := range CartesianProductN(New(1, 2, 3), New(1, 2, 3)) {
+		p = append(p, tuple)
+		if len(p) == 4 {
+			break
+		}
+	}
+	check(fmt.Sprintf("%v", p), len(p), "[[1 1] [1 2] [1 3] [2 1]]", 4, t)
+}
+
+func TestZip(t *testing.T) {
+	a := New(3, 1, 2)
+	b := New("z", "x", "y", "w")
+	z := Zip(a, b)
+	check(fmt.Sprintf("%v", z), len(z), "[{1 w} {2 x} {3 y}]", 3, t)
+	y := Zip(b, a)
+	check(fmt.Sprintf("%v", y), len(y), "[{w 1} {x 2} {y 3}]", 3, t)
+	e := Zip(a, New[string]())
+	check(fmt.Sprintf("%v", e), len(e), "[]", 0, t)
+}
+
+func TestMapFunc(t *testing.T) {
+	s := New(-2, -1, 0, 1, 2, 3)
+	u := Map(s, func(x int) int { return x * x })
+	check(u.String(), len(u), "{0 1 4 9}", 4, t)
+	v := Map(s, func(x int) string { return fmt.Sprint(x) })
+	check(v.String(), len(v), "{\"-1\" \"-2\" \"0\" \"1\" \"2\" \"3\"}",
+		6, t)
+}
+
+func TestMapToSlice(t *testing.T) {
+	s := New(-2, -1, 0, 1, 2)
+	u := MapToSlice(s, func(x int) int { return x * x })
+	sort.Ints(u)
+	check(fmt.Sprintf("%v", u), len(u), "[0 1 1 4 4]", 5, t)
+	v := MapToSlice(New("ab", "c"), func(x string) []byte {
+		return []byte(x)
+	})
+	if len(v) != 2 {
+		t.Errorf("expected 2 elements, got %d", len(v))
+	}
+}
+
+func TestToMap(t *testing.T) {
+	s := New("a", "bb", "ccc")
+	m := ToMap(s, func(x string) int { return len(x) })
+	check(fmt.Sprintf("%v", m), len(m), "map[a:1 bb:2 ccc:3]", 3, t)
+	e := ToMap(New[int](), func(x int) int { return x })
+	check(fmt.Sprintf("%v", e), len(e), "map[]", 0, t)
+}
+
+func TestConvert(t *testing.T) {
+	s := New(3, 1, 2)
+	u := Convert(s, func(x int) int64 { return int64(x) })
+	check(u.String(), len(u), "{1 2 3}", 3, t)
+	if !u.Contains(int64(2)) {
+		t.Error("expected set to contain int64(2)")
+	}
+}
+
+func TestFrozenSet(t *testing.T) {
+	s := New(3, 1, 2)
+	f := s.Frozen()
+	check(f.String(), f.Len(), "{1 2 3}", 3, t)
+	if !f.Contains(2) || f.Contains(4) {
+		t.Error("unexpected membership")
+	}
+	u := f.Union(New(4))
+	check(u.String(), len(u), "{1 2 3 4}", 4, t)
+	s.Add(5) // The frozen set is a view so sees this.
+	check(f.String(), f.Len(), "{1 2 3 5}", 4, t)
+	w := f.Thaw()
+	w.Add(6)
+	check(f.String(), f.Len(), "{1 2 3 5}", 4, t)
+	check(w.String(), len(w), "{1 2 3 5 6}", 5, t)
+}
+
+func TestFlatten(t *testing.T) {
+	s := Flatten([]Set[int]{New(1, 2), New(2, 3), New[int](), New(5)})
+	check(s.String(), len(s), "{1 2 3 5}", 4, t)
+	e := Flatten[int](nil)
+	check(e.String(), len(e), "{}", 0, t)
+	e.Add(1) // Must not be a nil map.
+}
+
+func TestGroupBy(t *testing.T) {
+	s := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	g := GroupBy(s, func(x int) bool { return x%2 == 0 })
+	if len(g) != 2 {
+		t.Errorf("expected 2 groups, got %d", len(g))
+	}
+	check(g[true].String(), len(g[true]), "{0 2 4 6 8}", 5, t)
+	check(g[false].String(), len(g[false]), "{1 3 5 7 9}", 5, t)
+	if u := g[true].Union(g[false]); !u.Equal(s) {
+		t.Errorf("expected %s, got %s", s, u)
+	}
+	g[true].Add(10)
+	if s.Contains(10) {
+		t.Error("expected groups to be independent of the original")
+	}
+	if e := GroupBy(New[int](), func(x int) int { return x }); len(e) != 0 {
+		t.Errorf("expected no groups, got %d", len(e))
+	}
+}
+
+func TestSum(t *testing.T) {
+	if total := Sum(New(1, 2, 3, 4)); total != 10 {
+		t.Errorf("expected 10, got %d", total)
+	}
+	if total := Sum(New(0.5, 1.25)); total != 1.75 {
+		t.Errorf("expected 1.75, got %g", total)
+	}
+	if total := Sum(New[int]()); total != 0 {
+		t.Errorf("expected 0, got %d", total)
+	}
+	if total := Sum(New[uint8](200, 100)); total != 44 {
+		t.Errorf("expected 44 (wrapped), got %d", total)
+	}
+}
+
+func TestNilSet(t *testing.T) {
+	var z Set[int]
+	s := New(1, 2, 3)
+	if !z.Equal(nil) || !z.Equal(New[int]()) || z.Equal(s) || s.Equal(z) {
+		t.Error("unexpected Equal result for nil set")
+	}
+	if z.Contains(1) {
+		t.Error("expected nil set not to contain 1")
+	}
+	if !z.IsEmpty() || z.Len() != 0 {
+		t.Error("expected nil set to be empty")
+	}
+	for _, u := range []Set[int]{z.Difference(s), s.Difference(z),
+		z.Union(z), z.Intersection(s), s.Intersection(z)} {
+		if u == nil {
+			t.Error("expected a non-nil set")
+		}
+	}
+	d := s.Difference(z)
+	check(d.String(), len(d), "{1 2 3}", 3, t)
+	u := z.Union(s)
+	check(u.String(), len(u), "{1 2 3}", 3, t)
+	u = s.Union(z)
+	check(u.String(), len(u), "{1 2 3}", 3, t)
+	if !z.IsDisjoint(s) || !s.IsDisjoint(z) || !z.IsDisjoint(z) {
+		t.Error("expected nil set to be disjoint")
+	}
+	check(z.String(), z.Len(), "{}", 0, t)
+}
+
+func TestMarshalJSONUnsorted(t *testing.T) {
+	s := New(3, 1, 2, 5, 4)
+	data, err := s.MarshalJSONUnsorted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u Set[int]
+	if err = json.Unmarshal(data, &u); err != nil {
+		t.Fatal(err)
+	}
+	if !u.Equal(s) {
+		t.Errorf("expected %v, got %v", s, u)
+	}
+	var z Set[string]
+	data, _ = z.MarshalJSONUnsorted()
+	check(string(data), 0, "[]", 0, t)
+}
+
+func TestMarshalJSONIndent(t *testing.T) {
+	data, err := New("b", "c", "a").MarshalJSONIndent("", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(string(data), 3, "[\n  \"a\",\n  \"b\",\n  \"c\"\n]", 3, t)
+	data, _ = New(1).MarshalJSONIndent("> ", "\t")
+	check(string(data), 1, "[\n> \t1\n> ]", 1, t)
+	var z Set[int]
+	data, _ = z.MarshalJSONIndent("", "  ")
+	check(string(data), 0, "[]", 0, t)
+}
+
+func TestJSON(t *testing.T) {
+	s := New(3, 1, 2)
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(string(data), len(s), "[1,2,3]", 3, t)
+	var z Set[string]
+	data, _ = json.Marshal(z)
+	check(string(data), 0, "[]", 0, t)
+	var u Set[int]
+	if err = json.Unmarshal([]byte("[5, 4, 5]"), &u); err != nil {
+		t.Fatal(err)
+	}
+	check(u.String(), len(u), "{4 5}", 2, t)
+	x := struct {
+		Tags Set[string] `json:"tags"`
+	}{}
+	if err = json.Unmarshal([]byte(`{"tags":["b","a"]}`), &x); err != nil {
+		t.Fatal(err)
+	}
+	check(x.Tags.String(), len(x.Tags), `{"a" "b"}`, 2, t)
+	if err = json.Unmarshal([]byte(` {"1":{},"7":{}}`), &u); err != nil {
+		t.Fatal(err)
+	}
+	check(u.String(), len(u), "{1 4 5 7}", 4, t)
+	if err = json.Unmarshal([]byte(`{"x":{}}`), &u); err == nil {
+		t.Error("expected error for JSON object with non-int key")
+	}
+	if err = json.Unmarshal([]byte(`"1"`), &u); err == nil {
+		t.Error("expected error for JSON string")
+	}
+	if err = u.UnmarshalJSON([]byte(" null ")); err != nil {
+		t.Fatal(err)
+	}
+	check(u.String(), len(u), "{1 4 5 7}", 4, t)
+	y := struct{ S Set[int] }{}
+	if err = json.Unmarshal([]byte(`{"S":null}`), &y); err != nil {
+		t.Fatal(err)
+	}
+	if y.S != nil {
+		t.Errorf("expected null to leave a nil set nil, got %#v", y.S)
+	}
+}
+
+func TestSQL(t *testing.T) {
+	s := New("b", "a")
+	value, err := s.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(value.(string), len(s), `["a","b"]`, 2, t)
+	u := New(99)
+	for _, src := range []any{"[1,2]", []byte("[2,1]")} {
+		if err = u.Scan(src); err != nil {
+			t.Fatal(err)
+		}
+		check(u.String(), len(u), "{1 2}", 2, t)
+	}
+	if err = u.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	check(u.String(), len(u), "{}", 0, t)
+	if err = u.Scan(42); err == nil {
+		t.Error("expected error for int source")
+	}
+	if err = u.Scan("[1,"); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+	var _ driver.Valuer = s
+	var _ sql.Scanner = &u
+}
+
+func TestYAML(t *testing.T) {
+	s := New(3, 1, 2)
+	value, err := s.MarshalYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(fmt.Sprintf("%v", value), len(s), "[1 2 3]", 3, t)
+	var u Set[int]
+	// Simulate the yaml package decoding the sequence [4, 5, 4].
+	err = u.UnmarshalYAML(func(v any) error {
+		*v.(*[]int) = []int{4, 5, 4}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(u.String(), len(u), "{4 5}", 2, t)
+	err = u.UnmarshalYAML(func(v any) error {
+		return fmt.Errorf("not a sequence")
+	})
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestCSVRecord(t *testing.T) {
+	s := New("b c", "a,d", `e"`)
+	var out strings.Builder
+	writer := csv.NewWriter(&out)
+	if err := writer.Write(s.ToCSVRecord()); err != nil {
+		t.Fatal(err)
+	}
+	writer.Flush()
+	check(out.String(), len(s), "\"a,d\",b c,\"e\"\"\"\n", 3, t)
+	record, err := csv.NewReader(strings.NewReader(out.String())).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := SetFromCSVRecord(record, func(field string) (string, error) {
+		return field, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.Equal(s) {
+		t.Errorf("expected %s, got %s", s, u)
+	}
+	w, err := SetFromCSVRecord([]string{"3", "1", "3"}, strconv.Atoi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(w.String(), len(w), "{1 3}", 2, t)
+	check(strings.Join(w.ToCSVRecord(), ","), len(w), "1,3", 2, t)
+	if _, err = SetFromCSVRecord([]string{"1", "x"}, strconv.Atoi); err == nil {
+		t.Error("expected error for invalid int")
+	}
+}
+
+func TestParse(t *testing.T) {
+	s, err := ParseIntSet(" {3 -1 2} ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(s.String(), len(s), "{-1 2 3}", 3, t)
+	u, err := ParseStringSet(`{"a b" "" "c\td"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(u.String(), len(u), `{"" "a b" "c\td"}`, 3, t)
+	e, err := ParseStringSet("{}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(e.String(), len(e), "{}", 0, t)
+	f := New(1.5, -2.25, 1e30)
+	g, err := Parse[float64](f.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Equal(g) {
+		t.Errorf("expected %s, got %s", f, g)
+	}
+	for _, text := range []string{"", "{1 2", "{1 two}", `{"1"}`} {
+		if _, err = ParseIntSet(text); err == nil {
+			t.Errorf("expected error for %q", text)
+		}
+	}
+	if _, err = ParseStringSet(`{"a" b}`); err == nil {
+		t.Error("expected error for unquoted string")
+	}
+}
+
+func TestMsgpack(t *testing.T) {
+	s := New(3, 1, 2)
+	data, err := s.MarshalMsgpack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(fmt.Sprintf("% x", data), len(s), "93 01 02 03", 3, t)
+	u := New(-1, -33, 200, -200, 70000, -70000, 1<<40, -(1 << 40))
+	data, err = u.MarshalMsgpack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v Set[int]
+	if err = v.UnmarshalMsgpack(data); err != nil {
+		t.Fatal(err)
+	}
+	if !u.Equal(v) {
+		t.Errorf("expected %s, got %s", u, v)
+	}
+	w := New("", "a", strings.Repeat("x", 40), strings.Repeat("y", 300))
+	data, _ = w.MarshalMsgpack()
+	z := New("old")
+	if err = z.UnmarshalMsgpack(data); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Union(New("old")).Equal(z) {
+		t.Errorf("expected %s plus \"old\", got %s", w, z)
+	}
+	if err = z.UnmarshalMsgpack([]byte{0x92, 0xa1, 'q', 0x01}); err == nil ||
+		z.Contains("q") {
+		t.Errorf("expected error and unchanged set, got %s %v", z, err)
+	}
+	f := New(1.5, -2.25)
+	data, _ = f.MarshalMsgpack()
+	g := New[float32]()
+	if err = g.UnmarshalMsgpack(data); err != nil {
+		t.Fatal(err)
+	}
+	check(g.String(), len(g), "{-2.25 1.5}", 2, t)
+	b := New(true, false)
+	data, _ = b.MarshalMsgpack()
+	check(fmt.Sprintf("% x", data), len(b), "92 c2 c3", 2, t)
+	big := New[int]()
+	for i := 0; i < 20; i++ {
+		big.Add(i)
+	}
+	data, _ = big.MarshalMsgpack()
+	var bigger Set[uint8]
+	if err = bigger.UnmarshalMsgpack(data); err != nil {
+		t.Fatal(err)
+	}
+	if len(bigger) != 20 || data[0] != 0xdc {
+		t.Errorf("unexpected result %s % x", bigger, data[:3])
+	}
+	if err = bigger.UnmarshalMsgpack([]byte{0xc0}); err != nil ||
+		len(bigger) != 20 {
+		t.Errorf("expected nil to do nothing, got %s %v", bigger, err)
+	}
+	var none Set[int]
+	if err = none.UnmarshalMsgpack([]byte{0xc0}); err != nil || none != nil {
+		t.Errorf("expected nil to leave a nil set nil, got %#v %v", none,
+			err)
+	}
+	var small Set[int8]
+	data, _ = New(200).MarshalMsgpack()
+	if err = small.UnmarshalMsgpack(data); err == nil {
+		t.Error("expected overflow error")
+	}
+	if err = small.UnmarshalMsgpack([]byte{0x92, 0x01}); err == nil {
+		t.Error("expected error for truncated data")
+	}
+	for _, data := range [][]byte{{0xdd, 0x0f, 0xff, 0xff, 0xff},
+		{0xdc, 0xff, 0xff, 0x01}, {0xdd, 0x00, 0x00}} {
+		if err = small.UnmarshalMsgpack(data); err == nil {
+			t.Errorf("expected error for bad array header % x", data)
+		}
+	}
+	if err = v.UnmarshalMsgpack([]byte{0x91, 0xa1, 'x'}); err == nil {
+		t.Error("expected error for string into int")
+	}
+	if _, err = New(Pair[int, int]{}).MarshalMsgpack(); err == nil {
+		t.Error("expected error for unsupported element type")
+	}
+}
+
+// The expected bytes are those produced by github.com/vmihailenco/msgpack.
+func TestMsgpackGolden(t *testing.T) {
+	for _, x := range []struct {
+		element int
+		exp     string
+	}{
+		{200, "cc c8"},
+		{-200, "d1 ff 38"},
+		{-70000, "d2 ff fe ee 90"},
+		{70000, "ce 00 01 11 70"},
+		{-(1 << 40), "d3 ff ff ff 00 00 00 00 00"},
+	} {
+		golden(New(x.element), "91 "+x.exp, t)
+	}
+	golden(New(uint64(1)<<40), "91 cf 00 00 01 00 00 00 00 00", t)
+	golden(New(uint64(math.MaxUint64)), "91 cf ff ff ff ff ff ff ff ff", t)
+	golden(New(strings.Repeat("x", 40)),
+		"91 d9 28"+strings.Repeat(" 78", 40), t)
+	golden(New(strings.Repeat("y", 300)),
+		"91 da 01 2c"+strings.Repeat(" 79", 300), t)
+}
+
+// golden checks that s marshals to the expected bytes (as "% x") and that
+// those bytes unmarshal back to s.
+func golden[T comparable](s Set[T], exp string, t *testing.T) {
+	t.Helper()
+	data, err := s.MarshalMsgpack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := fmt.Sprintf("% x", data); act != exp {
+		t.Errorf("%v: expected %s, got %s", s, exp, act)
+	}
+	var u Set[T]
+	if err = u.UnmarshalMsgpack(data); err != nil {
+		t.Fatal(err)
+	}
+	if !u.Equal(s) {
+		t.Errorf("expected %v, got %v", s, u)
+	}
+}
+
+func TestXML(t *testing.T) {
+	s := New(3, 1, 2)
+	data, err := xml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(string(data), len(s),
+		"<set><item>1</item><item>2</item><item>3</item></set>", 3, t)
+	data, _ = xml.Marshal(New[string]())
+	check(string(data), 0, "<set></set>", 0, t)
+	var u Set[int]
+	if err = xml.Unmarshal([]byte("<set><item>5</item><item>4</item>"+
+		"<item>5</item></set>"), &u); err != nil {
+		t.Fatal(err)
+	}
+	check(u.String(), len(u), "{4 5}", 2, t)
+	type config struct {
+		XMLName xml.Name    `xml:"config"`
+		Tags    Set[string] `xml:"tags"`
+	}
+	c := config{Tags: New("b", "a & c")}
+	data, err = xml.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(string(data), len(c.Tags), "<config><tags><item>a &amp; c</item>"+
+		"<item>b</item></tags></config>", 2, t)
+	var d config
+	if err = xml.Unmarshal(data, &d); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Tags.Equal(d.Tags) {
+		t.Errorf("expected %s, got %s", c.Tags, d.Tags)
+	}
+	if err = xml.Unmarshal([]byte("<set><item>x</item></set>"), &u); err == nil {
+		t.Error("expected error for invalid int")
+	}
+}
+
+func TestForEachParallel(t *testing.T) {
+	s := New[int]()
+	for i := 1; i <= 1000; i++ {
+		s.Add(i)
+	}
+	for _, workers := range []int{0, 1, 7} {
+		var mutex sync.Mutex
+		total := 0
+		s.ForEachParallel(workers, func(x int) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			total += x
+		})
+		if total != 500500 {
+			t.Errorf("expected 500500 with %d workers, got %d", workers,
+				total)
+		}
+	}
+	New[int]().ForEachParallel(2, func(int) { t.Error("unexpected call") })
+}
+
+func TestMapParallel(t *testing.T) {
+	s := New(-2, -1, 0, 1, 2, 3)
+	for _, workers := range []int{0, 1, 4} {
+		u := MapParallel(s, workers, func(x int) int { return x * x })
+		check(u.String(), len(u), "{0 1 4 9}", 4, t)
+	}
+	e := MapParallel(New[int](), 2, func(x int) string { return "" })
+	check(e.String(), len(e), "{}", 0, t)
+}
+
+func TestNewCap(t *testing.T) {
+	s := NewCap[int](10)
+	check(s.String(), len(s), "{}", 0, t)
+	s.Add(1, 2, 3)
+	Release(s)
+	Release[int](nil)
+	u := NewCap[int](10)
+	check(u.String(), len(u), "{}", 0, t)
+	u.Add(4)
+	check(u.String(), len(u), "{4}", 1, t)
+	w := NewCap[string](0) // A different type uses a different pool.
+	w.Add("a")
+	check(w.String(), len(w), `{"a"}`, 1, t)
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := make(Set[int], 100)
+		for j := 0; j < 100; j++ {
+			s.Add(j)
+		}
+	}
+}
+
+func BenchmarkNewCapRelease(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := NewCap[int](100)
+		for j := 0; j < 100; j++ {
+			s.Add(j)
+		}
+		Release(s)
+	}
+}
+
+func TestAllDisjoint(t *testing.T) {
+	if !AllDisjoint(New(1, 2), New(3), New[int](), New(4, 5)) {
+		t.Error("expected sets to be disjoint")
+	}
+	if AllDisjoint(New(1, 2), New(3), New(4, 2)) {
+		t.Error("expected sets not to be disjoint")
+	}
+	if !AllDisjoint[int]() || !AllDisjoint(New(1)) {
+		t.Error("expected zero or one sets to be disjoint")
+	}
+}
+
+func TestCovers(t *testing.T) {
+	universe := New(1, 2, 3, 4, 5)
+	if !Covers(universe, New(1, 2), New(3, 4, 6), New(5)) {
+		t.Error("expected parts to cover")
+	}
+	if Covers(universe, New(1, 2), New(3, 4, 6)) {
+		t.Error("expected parts not to cover")
+	}
+	if Covers(universe) {
+		t.Error("expected no parts not to cover")
+	}
+	if !Covers(New[int]()) {
+		t.Error("expected empty universe to be covered")
+	}
+}
+
+func TestIntersectionMatrix(t *testing.T) {
+	m := IntersectionMatrix([]Set[int]{New(1, 2, 3), New(2, 3, 4, 5),
+		New(5), New[int]()})
+	check(fmt.Sprintf("%v", m), len(m),
+		"[[3 2 0 0] [2 4 1 0] [0 1 1 0] [0 0 0 0]]", 4, t)
+	e := IntersectionMatrix[int](nil)
+	check(fmt.Sprintf("%v", e), len(e), "[]", 0, t)
+}
+
+func TestDedup(t *testing.T) {
+	d := Dedup([]int{3, 1, 3, 2, 1, 5})
+	check(fmt.Sprintf("%v", d), len(d), "[3 1 2 5]", 4, t)
+	d = DedupSorted([]int{3, 1, 3, 2, 1, 5})
+	check(fmt.Sprintf("%v", d), len(d), "[1 2 3 5]", 4, t)
+	e := Dedup[string](nil)
+	check(fmt.Sprintf("%v", e), len(e), "[]", 0, t)
+}
+
+func TestCounter(t *testing.T) {
+	c := Counter([]string{"a", "b", "a", "c", "a", "b"})
+	check(fmt.Sprintf("%v", c), len(c), "map[a:3 b:2 c:1]", 3, t)
+	if n := UniqueCount([]string{"a", "b", "a", "c", "a", "b"}); n != 3 {
+		t.Errorf("expected 3, got %d", n)
+	}
+	if n := UniqueCount[int](nil); n != 0 {
+		t.Errorf("expected 0, got %d", n)
+	}
+}
+
+func TestMultiset(t *testing.T) {
+	m := NewMultiset("a", "b", "a", "c", "a")
+	check(m.String(), m.Len(), `{"a":3 "b":1 "c":1}`, 5, t)
+	if m.Distinct() != 3 || m.Count("a") != 3 || m.Count("z") != 0 {
+		t.Errorf("unexpected counts for %s", m)
+	}
+	m.AddN("b", 2)
+	m.AddN("d", 0)
+	if !m.Remove("c") || m.Remove("c") || m.Remove("z") {
+		t.Error("unexpected Remove result")
+	}
+	check(m.String(), m.Len(), `{"a":3 "b":3}`, 6, t)
+	o := NewMultiset("a", "c", "b", "b", "b", "b")
+	u := m.Union(o)
+	check(u.String(), u.Len(), `{"a":3 "b":4 "c":1}`, 8, t)
+	x := m.Intersection(o)
+	check(x.String(), x.Len(), `{"a":1 "b":3}`, 4, t)
+	s := u.ToSet()
+	check(s.String(), len(s), `{"a" "b" "c"}`, 3, t)
+	e := NewMultiset[int]()
+	check(e.String(), e.Len(), "{}", 0, t)
+}
+
+func TestSumByCountBy(t *testing.T) {
+	s := New("three", "a", "sixteen", "be", "to")
+	length := func(x string) int { return len(x) }
+	if total := SumBy(s, length); total != 17 {
+		t.Errorf("expected 17, got %d", total)
+	}
+	half := func(x string) float64 { return float64(len(x)) / 2 }
+	if total := SumBy(s, half); total != 8.5 {
+		t.Errorf("expected 8.5, got %g", total)
+	}
+	c := CountBy(s, length)
+	check(fmt.Sprintf("%v", c), len(c), "map[1:1 2:2 5:1 7:1]", 4, t)
+	if total := SumBy(New[string](), length); total != 0 {
+		t.Errorf("expected 0, got %d", total)
+	}
+}
+
+func TestMinByMaxBy(t *testing.T) {
+	s := New("three", "a", "sixteen", "be")
+	length := func(x string) int { return len(x) }
+	if x, ok := MinBy(s, length); !ok || x != "a" {
+		t.Errorf("expected \"a\" true, got %q %t", x, ok)
+	}
+	if x, ok := MaxBy(s, length); !ok || x != "sixteen" {
+		t.Errorf("expected \"sixteen\" true, got %q %t", x, ok)
+	}
+	e := New[string]()
+	if x, ok := MinBy(e, length); ok || x != "" {
+		t.Errorf("expected \"\" false, got %q %t", x, ok)
+	}
+	if x, ok := MaxBy(e, length); ok || x != "" {
+		t.Errorf("expected \"\" false, got %q %t", x, ok)
+	}
+}
+
+func TestIntBitSet(t *testing.T) {
+	s := NewIntBitSet(3, 1, 64, 3, 130)
+	check(s.String(), s.Len(), "{1 3 64 130}", 4, t)
+	if n := s.Add(1, 2, 200); n != 2 {
+		t.Errorf("expected 2 added, got %d", n)
+	}
+	if n := s.Delete(2, 5, -1, 1000); n != 1 {
+		t.Errorf("expected 1 deleted, got %d", n)
+	}
+	if !s.Contains(200) || s.Contains(2) || s.Contains(-1) ||
+		s.Contains(5000) {
+		t.Error("unexpected membership")
+	}
+	o := NewIntBitSet(0, 3, 64, 65)
+	u := s.Union(o)
+	check(u.String(), u.Len(), "{0 1 3 64 65 130 200}", 7, t)
+	i := s.Intersection(o)
+	check(i.String(), i.Len(), "{3 64}", 2, t)
+	d := s.Difference(o)
+	check(d.String(), d.Len(), "{1 130 200}", 3, t)
+	d = o.Difference(s)
+	check(d.String(), d.Len(), "{0 65}", 2, t)
+	if !s.ToSet().Equal(New(1, 3, 64, 130, 200)) {
+		t.Errorf("unexpected ToSet %v", s.ToSet())
+	}
+	e := NewIntBitSet()
+	if !e.IsEmpty() || !NewIntBitSet(70).Intersection(o).IsEmpty() {
+		t.Error("expected empty")
+	}
+	check(e.String(), e.Len(), "{}", 0, t)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic adding a negative element")
+		}
+	}()
+	e.Add(-1)
+}
+
+func TestShardedSet(t *testing.T) {
+	s := NewSharded[int](4)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Add(i*100 + j)
+				_ = s.Contains(j)
+				_ = s.Len()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if s.Len() != 1000 {
+		t.Errorf("expected 1000 elements, got %d", s.Len())
+	}
+	if n := s.Delete(0, 1, 2, 5000); n != 3 {
+		t.Errorf("expected 3 deleted, got %d", n)
+	}
+	if s.Contains(1) || !s.Contains(999) {
+		t.Error("unexpected membership")
+	}
+	u := NewSharded(0, 3, 1, 2, 3)
+	if n := u.Add(4, 1); n != 1 {
+		t.Errorf("expected 1 added, got %d", n)
+	}
+	w := New(u.ToSlice()...)
+	check(w.String(), u.Len(), "{1 2 3 4}", 4, t)
+	w = u.Union(New(5, 1))
+	check(w.String(), len(w), "{1 2 3 4 5}", 5, t)
+	e := NewSharded[float64](2, 0.0)
+	if !e.Contains(math.Copysign(0, -1)) {
+		t.Error("expected -0 to be found as 0")
+	}
+	check(fmt.Sprintf("%v", NewSharded[int](3).ToSlice()), 0, "[]", 0, t)
+}
+
+func TestShardedSetZeroValue(t *testing.T) {
+	var s ShardedSet[int]
+	if s.Len() != 0 || s.Contains(1) || s.Delete(1) != 0 {
+		t.Error("expected an empty set")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Add(i, i+10)
+		}(i)
+	}
+	wg.Wait()
+	w := New(s.ToSlice()...)
+	check(w.String(), s.Len(), "{0 1 2 3 10 11 12 13}", 8, t)
+}
@@ -0,0 +1,142 @@
+// Copyright © 2022 Mark Summerfield. All rights reserved.
+// License: Apache-2.0
+
+package gset
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// IntBitSet is a set of non-negative ints stored as a bitmap, one bit per
+// possible element.
+//
+// For dense sets of small integers (e.g., IDs in a known range) this uses
+// far less memory than a Set[int] and its set operations work a word (64
+// elements) at a time. However, its size is proportional to its largest
+// element, so it is unsuitable for sparse sets or large values.
+//
+// See [NewIntBitSet] for how to create empty or populated int bit sets.
+type IntBitSet struct {
+	words []uint64
+}
+
+// NewIntBitSet returns a new int bit set containing the given elements (if
+// any). Panics if any element is negative.
+func NewIntBitSet(elements ...int) *IntBitSet {
+	set := &IntBitSet{}
+	set.Add(elements...)
+	return set
+}
+
+// String returns a human readable string representation of the set in the
+// same form as [Set.String].
+func (me *IntBitSet) String() string { return me.ToSet().String() }
+
+// Add adds the given element(s) to the set and returns how many of them
+// weren't already in the set. Panics if any element is negative.
+func (me *IntBitSet) Add(elements ...int) int {
+	count := 0
+	for _, element := range elements {
+		if element < 0 {
+			panic(fmt.Sprintf("gset: can't add negative element %d to an "+
+				"IntBitSet", element))
+		}
+		i, mask := element/64, uint64(1)<<(element%64)
+		if i >= len(me.words) {
+			me.words = append(me.words, make([]uint64,
+				i+1-len(me.words))...)
+		}
+		if me.words[i]&mask == 0 {
+			me.words[i] |= mask
+			count++
+		}
+	}
+	return count
+}
+
+// Delete deletes the given element(s) from the set and returns how many of
+// them were in the set.
+func (me *IntBitSet) Delete(elements ...int) int {
+	count := 0
+	for _, element := range elements {
+		if me.Contains(element) {
+			me.words[element/64] &^= uint64(1) << (element % 64)
+			count++
+		}
+	}
+	return count
+}
+
+// Contains returns true if element is in the set; otherwise returns false.
+func (me *IntBitSet) Contains(element int) bool {
+	if element < 0 || element/64 >= len(me.words) {
+		return false
+	}
+	return me.words[element/64]&(uint64(1)<<(element%64)) != 0
+}
+
+// Len returns the number of elements in the set.
+func (me *IntBitSet) Len() int {
+	count := 0
+	for _, word := range me.words {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// IsEmpty returns true if the set is empty; otherwise returns false.
+func (me *IntBitSet) IsEmpty() bool {
+	for _, word := range me.words {
+		if word != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new set that contains the elements from this set and from
+// the other set.
+func (me *IntBitSet) Union(other *IntBitSet) *IntBitSet {
+	longer, shorter := me.words, other.words
+	if len(shorter) > len(longer) {
+		longer, shorter = shorter, longer
+	}
+	words := append([]uint64(nil), longer...)
+	for i, word := range shorter {
+		words[i] |= word
+	}
+	return &IntBitSet{words: words}
+}
+
+// Intersection returns a new set that contains the elements this set has in
+// common with the other set.
+func (me *IntBitSet) Intersection(other *IntBitSet) *IntBitSet {
+	words := make([]uint64, min(len(me.words), len(other.words)))
+	for i := range words {
+		words[i] = me.words[i] & other.words[i]
+	}
+	return &IntBitSet{words: words}
+}
+
+// Difference returns a new set that contains the elements which are in this
+// set that are not in the other set.
+func (me *IntBitSet) Difference(other *IntBitSet) *IntBitSet {
+	words := append([]uint64(nil), me.words...)
+	for i := range min(len(words), len(other.words)) {
+		words[i] &^= other.words[i]
+	}
+	return &IntBitSet{words: words}
+}
+
+// ToSet returns a new [Set] containing this set's elements.
+func (me *IntBitSet) ToSet() Set[int] {
+	set := make(Set[int], me.Len())
+	for i, word := range me.words {
+		for word != 0 {
+			set[i*64+bits.TrailingZeros64(word)] = struct{}{}
+			word &= word - 1 // Clear the lowest set bit.
+		}
+	}
+	return set
+}
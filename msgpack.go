@@ -0,0 +1,304 @@
+// Copyright © 2022 Mark Summerfield. All rights reserved.
+// License: Apache-2.0
+
+package gset
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// MarshalMsgpack returns the set as a MessagePack array with its elements
+// sorted by <. This satisfies the msgpack.Marshaler interface of
+// github.com/vmihailenco/msgpack.
+// Only sets of strings, bools, and integer and floating-point types can be
+// marshalled.
+func (me Set[T]) MarshalMsgpack() ([]byte, error) {
+	elements := me.ToSortedSlice()
+	data := msgpackAppendLength(nil, len(elements), 0x90, 0xdc, 0xdd, 16)
+	var err error
+	for _, element := range elements {
+		data, err = msgpackAppend(data, reflect.ValueOf(element))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalMsgpack adds the elements from the given MessagePack array to
+// this set, allocating the set if it is nil. (Like [Set.UnmarshalJSON], any
+// existing elements are kept.) A MessagePack nil does nothing, and if an
+// error occurs the set is unchanged. This satisfies the
+// msgpack.Unmarshaler interface of github.com/vmihailenco/msgpack.
+// Only sets of strings, bools, and integer and floating-point types can be
+// unmarshalled.
+func (me *Set[T]) UnmarshalMsgpack(data []byte) error {
+	if len(data) == 1 && data[0] == 0xc0 { // nil
+		return nil
+	}
+	size, data, err := msgpackReadArrayLength(data)
+	if err != nil {
+		return err
+	}
+	if size > len(data) { // Every element takes at least one byte.
+		return fmt.Errorf("gset: msgpack array of %d elements has only %d "+
+			"bytes of data", size, len(data))
+	}
+	elements := make([]T, size)
+	for i := range elements {
+		if data, err = msgpackRead(data,
+			reflect.ValueOf(&elements[i]).Elem()); err != nil {
+			return err
+		}
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("gset: %d unexpected trailing msgpack bytes",
+			len(data))
+	}
+	if *me == nil {
+		*me = make(Set[T], size)
+	}
+	me.Add(elements...)
+	return nil
+}
+
+// msgpackAppendLength appends a length header using the fix code (e.g.,
+// 0x90 for arrays) if size < fixLimit, or else the 16 or 32-bit code.
+func msgpackAppendLength(data []byte, size int, fix, code16, code32 byte,
+	fixLimit int) []byte {
+	switch {
+	case size < fixLimit:
+		return append(data, fix|byte(size))
+	case size <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(data, code16),
+			uint16(size))
+	default:
+		return binary.BigEndian.AppendUint32(append(data, code32),
+			uint32(size))
+	}
+}
+
+func msgpackAppend(data []byte, value reflect.Value) ([]byte, error) {
+	switch value.Kind() {
+	case reflect.Bool:
+		if value.Bool() {
+			return append(data, 0xc3), nil
+		}
+		return append(data, 0xc2), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		x := value.Int()
+		switch {
+		case x >= 0:
+			return msgpackAppendUint(data, uint64(x)), nil
+		case x >= -32:
+			return append(data, byte(x)), nil
+		case x >= math.MinInt8:
+			return append(data, 0xd0, byte(x)), nil
+		case x >= math.MinInt16:
+			return binary.BigEndian.AppendUint16(append(data, 0xd1),
+				uint16(x)), nil
+		case x >= math.MinInt32:
+			return binary.BigEndian.AppendUint32(append(data, 0xd2),
+				uint32(x)), nil
+		default:
+			return binary.BigEndian.AppendUint64(append(data, 0xd3),
+				uint64(x)), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return msgpackAppendUint(data, value.Uint()), nil
+	case reflect.Float32:
+		return binary.BigEndian.AppendUint32(append(data, 0xca),
+			math.Float32bits(float32(value.Float()))), nil
+	case reflect.Float64:
+		return binary.BigEndian.AppendUint64(append(data, 0xcb),
+			math.Float64bits(value.Float())), nil
+	case reflect.String:
+		s := value.String()
+		if len(s) <= math.MaxUint8 && len(s) >= 32 {
+			data = append(data, 0xd9, byte(len(s)))
+		} else {
+			data = msgpackAppendLength(data, len(s), 0xa0, 0xda, 0xdb, 32)
+		}
+		return append(data, s...), nil
+	}
+	return nil, fmt.Errorf("gset: can't marshal %s elements to msgpack",
+		value.Type())
+}
+
+func msgpackAppendUint(data []byte, x uint64) []byte {
+	switch {
+	case x <= math.MaxInt8:
+		return append(data, byte(x))
+	case x <= math.MaxUint8:
+		return append(data, 0xcc, byte(x))
+	case x <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(data, 0xcd), uint16(x))
+	case x <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(data, 0xce), uint32(x))
+	default:
+		return binary.BigEndian.AppendUint64(append(data, 0xcf), x)
+	}
+}
+
+func msgpackReadArrayLength(data []byte) (int, []byte, error) {
+	if len(data) > 0 {
+		code := data[0]
+		switch {
+		case code&0xf0 == 0x90:
+			return int(code & 0x0f), data[1:], nil
+		case code == 0xdc && len(data) >= 3:
+			return int(binary.BigEndian.Uint16(data[1:])), data[3:], nil
+		case code == 0xdd && len(data) >= 5:
+			return int(binary.BigEndian.Uint32(data[1:])), data[5:], nil
+		}
+	}
+	return 0, nil, fmt.Errorf("gset: expected a msgpack array")
+}
+
+// msgpackRead reads one value from data into value and returns the rest of
+// data.
+func msgpackRead(data []byte, value reflect.Value) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("gset: unexpected end of msgpack data")
+	}
+	code := data[0]
+	data = data[1:]
+	// Reads n bytes as a big-endian unsigned integer.
+	readUint := func(n int) (uint64, bool) {
+		if len(data) < n {
+			return 0, false
+		}
+		var x uint64
+		for _, b := range data[:n] {
+			x = x<<8 | uint64(b)
+		}
+		data = data[n:]
+		return x, true
+	}
+	var number any // int64, uint64, or float64
+	var text string
+	isText := false
+	ok := true
+	switch {
+	case code == 0xc2 || code == 0xc3:
+		if value.Kind() != reflect.Bool {
+			return nil, msgpackMismatch("bool", value)
+		}
+		value.SetBool(code == 0xc3)
+		return data, nil
+	case code <= 0x7f:
+		number = uint64(code)
+	case code >= 0xe0:
+		number = int64(int8(code))
+	case code >= 0xcc && code <= 0xcf:
+		var x uint64
+		x, ok = readUint(1 << (code - 0xcc))
+		number = x
+	case code >= 0xd0 && code <= 0xd3:
+		var x uint64
+		size := 1 << (code - 0xd0)
+		x, ok = readUint(size)
+		shift := 64 - 8*size
+		number = int64(x<<shift) >> shift // Sign-extend.
+	case code == 0xca:
+		var x uint64
+		x, ok = readUint(4)
+		number = float64(math.Float32frombits(uint32(x)))
+	case code == 0xcb:
+		var x uint64
+		x, ok = readUint(8)
+		number = math.Float64frombits(x)
+	case code&0xe0 == 0xa0 || (code >= 0xd9 && code <= 0xdb):
+		size := uint64(code & 0x1f)
+		if code >= 0xd9 {
+			size, ok = readUint(1 << (code - 0xd9))
+		}
+		if ok && uint64(len(data)) >= size {
+			text, data = string(data[:size]), data[size:]
+			isText = true
+		} else {
+			ok = false
+		}
+	default:
+		return nil, fmt.Errorf("gset: unsupported msgpack code 0x%02x", code)
+	}
+	if !ok {
+		return nil, fmt.Errorf("gset: unexpected end of msgpack data")
+	}
+	if isText {
+		if value.Kind() != reflect.String {
+			return nil, msgpackMismatch("string", value)
+		}
+		value.SetString(text)
+		return data, nil
+	}
+	return data, msgpackSetNumber(number, value)
+}
+
+func msgpackSetNumber(number any, value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		var x int64
+		switch n := number.(type) {
+		case int64:
+			x = n
+		case uint64:
+			if n > math.MaxInt64 {
+				return msgpackOverflow(n, value)
+			}
+			x = int64(n)
+		default:
+			return msgpackMismatch("float", value)
+		}
+		if value.OverflowInt(x) {
+			return msgpackOverflow(x, value)
+		}
+		value.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		var x uint64
+		switch n := number.(type) {
+		case uint64:
+			x = n
+		case int64:
+			if n < 0 {
+				return msgpackOverflow(n, value)
+			}
+			x = uint64(n)
+		default:
+			return msgpackMismatch("float", value)
+		}
+		if value.OverflowUint(x) {
+			return msgpackOverflow(x, value)
+		}
+		value.SetUint(x)
+	case reflect.Float32, reflect.Float64:
+		switch n := number.(type) {
+		case float64:
+			value.SetFloat(n)
+		case int64:
+			value.SetFloat(float64(n))
+		case uint64:
+			value.SetFloat(float64(n))
+		}
+	default:
+		return msgpackMismatch("number", value)
+	}
+	return nil
+}
+
+func msgpackMismatch(what string, value reflect.Value) error {
+	return fmt.Errorf("gset: can't unmarshal msgpack %s into %s", what,
+		value.Type())
+}
+
+func msgpackOverflow(number any, value reflect.Value) error {
+	return fmt.Errorf("gset: msgpack number %v overflows %s", number,
+		value.Type())
+}
@@ -0,0 +1,139 @@
+// Copyright © 2022 Mark Summerfield. All rights reserved.
+// License: Apache-2.0
+
+package gset
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Multiset is a generic multiset (bag) type based on a map of each element
+// to the number of times it occurs.
+//
+// Every count in a Multiset is positive: elements whose count falls to zero
+// are deleted.
+//
+// See [NewMultiset] for how to create empty or populated multisets.
+type Multiset[T comparable] map[T]int
+
+// NewMultiset returns a new multiset containing the given elements (if any),
+// each counted as many times as it is given.
+// If no elements are given, the type must be specified since it can't be
+// inferred.
+func NewMultiset[T comparable](elements ...T) Multiset[T] {
+	multiset := make(Multiset[T], len(elements))
+	multiset.Add(elements...)
+	return multiset
+}
+
+// String returns a human readable string representation of the multiset.
+// If it has <= 100 distinct elements, returns "{e1:c1 e2:c2 ... eN:cN}"
+// with elements sorted by < and each followed by its count; otherwise
+// returns "{…N distinct elements…}".
+func (me Multiset[T]) String() string {
+	if len(me) > maxDisplayableElements {
+		return fmt.Sprintf("{…%d distinct elements…}", len(me))
+	}
+	var s strings.Builder
+	s.WriteString("{")
+	sep := ""
+	for _, element := range me.ToSet().ToSortedSlice() {
+		s.WriteString(sep)
+		if selement, ok := any(element).(string); ok {
+			fmt.Fprintf(&s, "%q:%d", selement, me[element])
+		} else {
+			fmt.Fprintf(&s, "%v:%d", element, me[element])
+		}
+		sep = " "
+	}
+	s.WriteString("}")
+	return s.String()
+}
+
+// Add adds one occurrence of each of the given element(s).
+func (me Multiset[T]) Add(elements ...T) {
+	for _, element := range elements {
+		me[element]++
+	}
+}
+
+// AddN adds n occurrences of the given element. Does nothing if n <= 0.
+func (me Multiset[T]) AddN(element T, n int) {
+	if n > 0 {
+		me[element] += n
+	}
+}
+
+// Remove removes one occurrence of the given element and returns true, or
+// returns false if the element isn't in the multiset.
+func (me Multiset[T]) Remove(element T) bool {
+	count, found := me[element]
+	if !found {
+		return false
+	}
+	if count > 1 {
+		me[element] = count - 1
+	} else {
+		delete(me, element)
+	}
+	return true
+}
+
+// Count returns how many times the given element occurs in the multiset.
+func (me Multiset[T]) Count(element T) int { return me[element] }
+
+// Len returns the total number of occurrences of all the elements.
+// See also [Multiset.Distinct].
+func (me Multiset[T]) Len() int {
+	total := 0
+	for _, count := range me {
+		total += count
+	}
+	return total
+}
+
+// Distinct returns the number of distinct elements; this is just a
+// convenience for len(m). See also [Multiset.Len].
+func (me Multiset[T]) Distinct() int { return len(me) }
+
+// Union returns a new multiset that contains every element from this
+// multiset and from the other multiset, each with the larger of its two
+// counts.
+func (me Multiset[T]) Union(other Multiset[T]) Multiset[T] {
+	union := make(Multiset[T], len(me))
+	for element, count := range me {
+		union[element] = count
+	}
+	for element, count := range other {
+		if count > union[element] {
+			union[element] = count
+		}
+	}
+	return union
+}
+
+// Intersection returns a new multiset that contains the elements this
+// multiset has in common with the other multiset, each with the smaller of
+// its two counts.
+func (me Multiset[T]) Intersection(other Multiset[T]) Multiset[T] {
+	intersection := Multiset[T]{}
+	for element, count := range me {
+		if otherCount, found := other[element]; found {
+			if otherCount < count {
+				count = otherCount
+			}
+			intersection[element] = count
+		}
+	}
+	return intersection
+}
+
+// ToSet returns a new set containing this multiset's distinct elements.
+func (me Multiset[T]) ToSet() Set[T] {
+	set := make(Set[T], len(me))
+	for element := range me {
+		set[element] = struct{}{}
+	}
+	return set
+}
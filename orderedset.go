@@ -0,0 +1,141 @@
+// Copyright © 2022 Mark Summerfield. All rights reserved.
+// License: Apache-2.0
+
+package gset
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrderedSet is a set that remembers the order in which its elements were
+// added.
+//
+// Re-adding an element that is already present does not change its
+// position. Deleting is O(1) amortized: deleted elements are marked and
+// only compacted away once they outnumber the live ones.
+//
+// See [NewOrderedSet] for how to create empty or populated ordered sets.
+type OrderedSet[T comparable] struct {
+	index    map[T]int // element → position in entries
+	entries  []orderedEntry[T]
+	nDeleted int
+}
+
+type orderedEntry[T comparable] struct {
+	element T
+	deleted bool
+}
+
+// NewOrderedSet returns a new ordered set containing the given elements (if
+// any) in the order given.
+// If no elements are given, the type must be specified since it can't be
+// inferred.
+func NewOrderedSet[T comparable](elements ...T) *OrderedSet[T] {
+	set := &OrderedSet[T]{index: make(map[T]int, len(elements)),
+		entries: make([]orderedEntry[T], 0, len(elements))}
+	set.Add(elements...)
+	return set
+}
+
+// String returns a human readable string representation of the set.
+// If Len() <= 100, returns "{e1 e2 ... eN}" with elements in insertion
+// order; otherwise returns "{…N elements…}" where N is Len().
+func (me *OrderedSet[T]) String() string {
+	if me.Len() > maxDisplayableElements {
+		return fmt.Sprintf("{…%d elements…}", me.Len())
+	}
+	var s strings.Builder
+	s.WriteString("{")
+	sep := ""
+	for _, element := range me.ToSlice() {
+		s.WriteString(sep)
+		if selement, ok := any(element).(string); ok {
+			fmt.Fprintf(&s, "%q", selement)
+		} else {
+			fmt.Fprintf(&s, "%v", element)
+		}
+		sep = " "
+	}
+	s.WriteString("}")
+	return s.String()
+}
+
+// ToSlice returns this set's elements as a slice in insertion order.
+func (me *OrderedSet[T]) ToSlice() []T {
+	result := make([]T, 0, me.Len())
+	for _, entry := range me.entries {
+		if !entry.deleted {
+			result = append(result, entry.element)
+		}
+	}
+	return result
+}
+
+// Add adds the given element(s) to the end of the set and returns how many
+// of them weren't already in the set; elements that are already present
+// keep their existing position.
+func (me *OrderedSet[T]) Add(elements ...T) int {
+	if me.index == nil {
+		me.index = make(map[T]int, len(elements))
+	}
+	count := 0
+	for _, element := range elements {
+		if _, found := me.index[element]; !found {
+			me.index[element] = len(me.entries)
+			me.entries = append(me.entries, orderedEntry[T]{element, false})
+			count++
+		}
+	}
+	return count
+}
+
+// Delete deletes the given element(s) from the set and returns how many of
+// them were in the set.
+func (me *OrderedSet[T]) Delete(elements ...T) int {
+	count := 0
+	for _, element := range elements {
+		if i, found := me.index[element]; found {
+			delete(me.index, element)
+			var zero T
+			me.entries[i] = orderedEntry[T]{zero, true}
+			me.nDeleted++
+			count++
+		}
+	}
+	if me.nDeleted > len(me.index) {
+		me.compact()
+	}
+	return count
+}
+
+func (me *OrderedSet[T]) compact() {
+	entries := make([]orderedEntry[T], 0, len(me.index))
+	for _, entry := range me.entries {
+		if !entry.deleted {
+			me.index[entry.element] = len(entries)
+			entries = append(entries, entry)
+		}
+	}
+	me.entries = entries
+	me.nDeleted = 0
+}
+
+// Clear deletes all the elements to make this an empty set.
+func (me *OrderedSet[T]) Clear() {
+	me.index = map[T]int{}
+	me.entries = nil
+	me.nDeleted = 0
+}
+
+// Len returns the number of elements in the set.
+func (me *OrderedSet[T]) Len() int { return len(me.index) }
+
+// IsEmpty returns true if the set is empty; otherwise returns false.
+func (me *OrderedSet[T]) IsEmpty() bool { return len(me.index) == 0 }
+
+// Contains returns true if element is in the set; otherwise returns false.
+func (me *OrderedSet[T]) Contains(element T) bool {
+	_, found := me.index[element]
+	return found
+}
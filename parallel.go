@@ -0,0 +1,54 @@
+// Copyright © 2022 Mark Summerfield. All rights reserved.
+// License: Apache-2.0
+
+package gset
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ForEachParallel calls fn once for each of this set's elements using the
+// given number of worker goroutines, and returns when every call has
+// finished. If workers <= 0, runtime.GOMAXPROCS(0) workers are used.
+// Since fn is called concurrently it must be safe to do so, and since the
+// set is read while fn is called, fn must not change the set.
+func (me Set[T]) ForEachParallel(workers int, fn func(T)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	elements := make(chan T)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for element := range elements {
+				fn(element)
+			}
+		}()
+	}
+	for element := range me {
+		elements <- element
+	}
+	close(elements)
+	wg.Wait()
+}
+
+// MapParallel returns a new set containing fn(element) for every element in
+// s, calling fn concurrently using the given number of worker goroutines.
+// If workers <= 0, runtime.GOMAXPROCS(0) workers are used.
+// As with [Map], if fn maps two or more elements to the same value, the new
+// set will contain that value only once. Since fn is called concurrently it
+// must be safe to do so.
+func MapParallel[T, U comparable](s Set[T], workers int, fn func(T) U) Set[U] {
+	result := make(Set[U], len(s))
+	var mutex sync.Mutex
+	s.ForEachParallel(workers, func(element T) {
+		value := fn(element)
+		mutex.Lock()
+		defer mutex.Unlock()
+		result[value] = struct{}{}
+	})
+	return result
+}
@@ -0,0 +1,171 @@
+// Copyright © 2022 Mark Summerfield. All rights reserved.
+// License: Apache-2.0
+
+package gset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Parse returns a new set containing the elements from text in the format
+// produced by [Set.String], e.g., "{1 2 3}" or "{\"a\" \"b c\"}", with
+// optional surrounding whitespace.
+// Only sets of strings, bools, and integer and floating-point types can be
+// parsed.
+// See also [ParseIntSet] and [ParseStringSet].
+func Parse[T comparable](text string) (Set[T], error) {
+	elements, err := parseElements[T](text)
+	if err != nil {
+		return nil, err
+	}
+	return New(elements...), nil
+}
+
+// ParseIntSet returns a new set of ints from text such as "{1 2 3}".
+// See [Parse].
+func ParseIntSet(text string) (Set[int], error) { return Parse[int](text) }
+
+// ParseStringSet returns a new set of strings from text such as
+// "{\"a\" \"b c\"}". See [Parse].
+func ParseStringSet(text string) (Set[string], error) {
+	return Parse[string](text)
+}
+
+// parseElements parses text of the form produced by [Set.String], i.e.,
+// "{e1 e2 ... eN}", with optional surrounding whitespace, and returns the
+// elements. String elements must be quoted; other elements must not be.
+func parseElements[T comparable](text string) ([]T, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "{") || !strings.HasSuffix(text, "}") {
+		return nil, fmt.Errorf("gset: expected {...}, got %q", text)
+	}
+	text = text[1 : len(text)-1]
+	var elements []T
+	for {
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		if text == "" {
+			break
+		}
+		var token string
+		quoted := text[0] == '"'
+		if quoted != isString[T]() {
+			return nil, fmt.Errorf("gset: string elements must be quoted "+
+				"and others must not be: %q", text)
+		}
+		if quoted {
+			end := quotedEnd(text)
+			if end < 0 {
+				return nil, fmt.Errorf("gset: unterminated string %q", text)
+			}
+			unquoted, err := strconv.Unquote(text[:end])
+			if err != nil {
+				return nil, fmt.Errorf("gset: invalid string %q: %w",
+					text[:end], err)
+			}
+			token, text = unquoted, text[end:]
+		} else {
+			end := strings.IndexFunc(text, unicode.IsSpace)
+			if end < 0 {
+				end = len(text)
+			}
+			token, text = text[:end], text[end:]
+		}
+		element, err := parseElement[T](token)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+	return elements, nil
+}
+
+func isString[T comparable]() bool {
+	var zero T
+	_, ok := any(zero).(string)
+	return ok
+}
+
+// quotedEnd returns the index just past the closing quote of the quoted
+// string that text starts with, or -1 if there's no closing quote.
+func quotedEnd(text string) int {
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// parseElement converts the given token to an element of type T.
+// Only string, bool, and the integer and floating-point types are
+// supported.
+func parseElement[T comparable](token string) (T, error) {
+	var element T
+	var err error
+	switch p := any(&element).(type) {
+	case *string:
+		*p = token
+	case *bool:
+		*p, err = strconv.ParseBool(token)
+	case *int:
+		var x int64
+		x, err = strconv.ParseInt(token, 10, 0)
+		*p = int(x)
+	case *int8:
+		var x int64
+		x, err = strconv.ParseInt(token, 10, 8)
+		*p = int8(x)
+	case *int16:
+		var x int64
+		x, err = strconv.ParseInt(token, 10, 16)
+		*p = int16(x)
+	case *int32:
+		var x int64
+		x, err = strconv.ParseInt(token, 10, 32)
+		*p = int32(x)
+	case *int64:
+		*p, err = strconv.ParseInt(token, 10, 64)
+	case *uint:
+		var x uint64
+		x, err = strconv.ParseUint(token, 10, 0)
+		*p = uint(x)
+	case *uint8:
+		var x uint64
+		x, err = strconv.ParseUint(token, 10, 8)
+		*p = uint8(x)
+	case *uint16:
+		var x uint64
+		x, err = strconv.ParseUint(token, 10, 16)
+		*p = uint16(x)
+	case *uint32:
+		var x uint64
+		x, err = strconv.ParseUint(token, 10, 32)
+		*p = uint32(x)
+	case *uint64:
+		*p, err = strconv.ParseUint(token, 10, 64)
+	case *uintptr:
+		var x uint64
+		x, err = strconv.ParseUint(token, 10, 64)
+		*p = uintptr(x)
+	case *float32:
+		var x float64
+		x, err = strconv.ParseFloat(token, 32)
+		*p = float32(x)
+	case *float64:
+		*p, err = strconv.ParseFloat(token, 64)
+	default:
+		return element, fmt.Errorf("gset: can't parse elements of type %T",
+			element)
+	}
+	if err != nil {
+		return element, fmt.Errorf("gset: invalid %T element %q: %w",
+			element, token, err)
+	}
+	return element, nil
+}
@@ -0,0 +1,44 @@
+// Copyright © 2022 Mark Summerfield. All rights reserved.
+// License: Apache-2.0
+
+package gset
+
+import (
+	"reflect"
+	"sync"
+)
+
+// pools holds a *sync.Pool of released sets for each set type.
+var pools sync.Map // reflect.Type → *sync.Pool
+
+func poolFor[T comparable]() *sync.Pool {
+	key := reflect.TypeFor[Set[T]]()
+	if pool, ok := pools.Load(key); ok {
+		return pool.(*sync.Pool)
+	}
+	pool, _ := pools.LoadOrStore(key, &sync.Pool{})
+	return pool.(*sync.Pool)
+}
+
+// NewCap returns an empty set, reusing one previously passed to [Release]
+// if one is available (whatever its capacity), or else creating a new one
+// with room for the given number of elements.
+//
+// In loops that create many short-lived sets, using NewCap to create them
+// and Release when each is finished with, can greatly reduce allocations.
+func NewCap[T comparable](capacity int) Set[T] {
+	if set, ok := poolFor[T]().Get().(Set[T]); ok {
+		return set
+	}
+	return make(Set[T], capacity)
+}
+
+// Release clears the given set and makes it available for reuse by
+// [NewCap]. The set must not be used after it has been released.
+func Release[T comparable](s Set[T]) {
+	if s == nil {
+		return
+	}
+	clear(s)
+	poolFor[T]().Put(s)
+}
@@ -0,0 +1,173 @@
+// Copyright © 2022 Mark Summerfield. All rights reserved.
+// License: Apache-2.0
+
+package gset
+
+import "sync"
+
+// SafeSet is a set that is safe to use from multiple goroutines.
+//
+// Methods that only read take a read lock; methods that change the set
+// take a write lock. Methods that return a set return an independent copy.
+//
+// The zero value is an empty set ready to use. See [NewSafe] for how to
+// create empty or populated safe sets.
+type SafeSet[T comparable] struct {
+	sync.RWMutex
+	set Set[T]
+}
+
+// NewSafe returns a new safe set containing the given elements (if any).
+// If no elements are given, the type must be specified since it can't be
+// inferred.
+func NewSafe[T comparable](elements ...T) *SafeSet[T] {
+	return &SafeSet[T]{set: New(elements...)}
+}
+
+// String returns a human readable string representation of the set.
+// See [Set.String].
+func (me *SafeSet[T]) String() string {
+	me.RLock()
+	defer me.RUnlock()
+	return me.set.String()
+}
+
+// Snapshot returns a new (non-safe) set that is a copy of this set's
+// current elements, e.g., to iterate over without holding a lock. Since it
+// copies, this takes O(n) time.
+func (me *SafeSet[T]) Snapshot() Set[T] {
+	me.RLock()
+	defer me.RUnlock()
+	return me.set.Copy()
+}
+
+// ToSlice returns this set's elements as a slice.
+func (me *SafeSet[T]) ToSlice() []T {
+	me.RLock()
+	defer me.RUnlock()
+	return me.set.ToSlice()
+}
+
+// ToSortedSlice returns this set's elements as a slice with the elements
+// sorted using <.
+func (me *SafeSet[T]) ToSortedSlice() []T {
+	me.RLock()
+	defer me.RUnlock()
+	return me.set.ToSortedSlice()
+}
+
+// Add adds the given element(s) to the set and returns how many of them
+// weren't already in the set.
+func (me *SafeSet[T]) Add(elements ...T) int {
+	me.Lock()
+	defer me.Unlock()
+	if me.set == nil {
+		me.set = Set[T]{}
+	}
+	return me.set.Add(elements...)
+}
+
+// Delete deletes the given element(s) from the set and returns how many of
+// them were in the set.
+func (me *SafeSet[T]) Delete(elements ...T) int {
+	me.Lock()
+	defer me.Unlock()
+	return me.set.Delete(elements...)
+}
+
+// Clear deletes all the elements to make this an empty set.
+func (me *SafeSet[T]) Clear() {
+	me.Lock()
+	defer me.Unlock()
+	me.set.Clear()
+}
+
+// Replace replaces this set's elements with a copy of the other set's in a
+// single step, so that other goroutines see either all the old elements or
+// all the new ones, never a mixture.
+func (me *SafeSet[T]) Replace(other Set[T]) {
+	set := other.Copy()
+	me.Lock()
+	defer me.Unlock()
+	me.set = set
+}
+
+// Len returns the number of elements in the set.
+func (me *SafeSet[T]) Len() int {
+	me.RLock()
+	defer me.RUnlock()
+	return len(me.set)
+}
+
+// IsEmpty returns true if the set is empty; otherwise returns false.
+func (me *SafeSet[T]) IsEmpty() bool {
+	me.RLock()
+	defer me.RUnlock()
+	return len(me.set) == 0
+}
+
+// Contains returns true if element is in the set; otherwise returns false.
+func (me *SafeSet[T]) Contains(element T) bool {
+	me.RLock()
+	defer me.RUnlock()
+	return me.set.Contains(element)
+}
+
+// Difference returns a new set that contains the elements which are in this
+// set that are not in the other set.
+func (me *SafeSet[T]) Difference(other Set[T]) Set[T] {
+	me.RLock()
+	defer me.RUnlock()
+	return me.set.Difference(other)
+}
+
+// SymmetricDifference returns a new set that contains the elements which
+// are in this set or the other set—but not in both sets.
+func (me *SafeSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	me.RLock()
+	defer me.RUnlock()
+	return me.set.SymmetricDifference(other)
+}
+
+// Intersection returns a new set that contains the elements this set has in
+// common with the other set.
+func (me *SafeSet[T]) Intersection(other Set[T]) Set[T] {
+	me.RLock()
+	defer me.RUnlock()
+	return me.set.Intersection(other)
+}
+
+// Union returns a new set that contains the elements from this set and from
+// the other set.
+func (me *SafeSet[T]) Union(other Set[T]) Set[T] {
+	me.RLock()
+	defer me.RUnlock()
+	return me.set.Union(other)
+}
+
+// Unite adds all the elements from the other set(s) that aren't already in
+// this set to this set and returns how many were added.
+func (me *SafeSet[T]) Unite(others ...Set[T]) int {
+	me.Lock()
+	defer me.Unlock()
+	if me.set == nil {
+		me.set = Set[T]{}
+	}
+	return me.set.Unite(others...)
+}
+
+// Equal returns true if this set has the same elements as the other set;
+// otherwise returns false.
+func (me *SafeSet[T]) Equal(other Set[T]) bool {
+	me.RLock()
+	defer me.RUnlock()
+	return me.set.Equal(other)
+}
+
+// IsDisjoint returns true if this set has no elements in common with the
+// other set; otherwise returns false.
+func (me *SafeSet[T]) IsDisjoint(other Set[T]) bool {
+	me.RLock()
+	defer me.RUnlock()
+	return me.set.IsDisjoint(other)
+}